@@ -2,37 +2,220 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
-	"time"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/volunteerService-backend/config"
 	"github.com/volunteerService-backend/db"
+	"github.com/volunteerService-backend/grpcapi"
 	"github.com/volunteerService-backend/handlers"
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/ratelimit"
 	"github.com/volunteerService-backend/services"
+	"github.com/volunteerService-backend/worker"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
 )
 
 type Application struct {
-	Models services.Models
+	Models *services.Models
 }
 
 func main() {
-	mongoClient, err := db.ConnectToMongo()
+	configFile := flag.String("config", "", "path to a YAML config file (defaults to $CONFIG_FILE)")
+	migrate := flag.String("migrate", "", "run schema migrations \"up\" or \"down\", or print their \"status\", then exit")
+	migrateTo := flag.Int("migrate-to", -1, "the version to migrate up or down to (defaults to the latest for up, one step back for down)")
+	flag.Parse()
+
+	// JSON logs everywhere, including anything still written through the log package
+	slog.SetDefault(logging.New(os.Stdout))
+
+	cfg, err := config.Load(*configFile)
 	if err != nil {
-		log.Panic(err)
+		fatal("Error loading configuration", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	// The memory backend runs without a database, for local development
+	var mongoClient *mongo.Client
+	if cfg.Storage.Backend == config.StorageMongo {
+		mongoClient, err = db.ConnectToMongo(cfg.Mongo)
+		if err != nil {
+			fatal("Error connecting to mongo", err)
+		}
+	} else {
+		slog.Warn("Using the in-memory storage backend, data will be lost on restart")
+	}
+
+	models := services.New(mongoClient, cfg)
+
+	if *migrate != "" {
+		err := runMigrations(models, *migrate, *migrateTo)
+		disconnect(mongoClient, cfg.Server)
+		if err != nil {
+			fatal("Error running migrations", err)
+		}
+		return
+	}
+
+	if mongoClient != nil && cfg.Mongo.AutoMigrate {
+		// Another instance starting at the same time may hold the lock, and readiness
+		// reports the schema out of date until one of them is done
+		if err := runMigrations(models, "up", -1); err != nil {
+			slog.Error("Error migrating, readiness will report migrations pending", "error", err)
+		}
+	}
+
+	counters := ratelimit.NewMemoryStore()
+	if cfg.Server.RateLimit.Store == config.RateLimitStoreMongo {
+		storeCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		counters, err = ratelimit.NewMongoStore(storeCtx, mongoClient.Database(cfg.Mongo.Database).Collection(services.RateLimitCollection))
+		cancel()
+		if err != nil {
+			fatal("Error setting up the rate limit store", err)
+		}
+	}
 
-	defer func() {
-		if err = mongoClient.Disconnect(ctx); err != nil {
-			panic(err)
+	workers := worker.NewGroup()
+	if sweeper, ok := counters.(ratelimit.Sweeper); ok {
+		workers.Go("ratelimit-sweep", sweeper.Sweep)
+	}
+
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:      handlers.CreateRouter(cfg.Server, handlers.New(models, workers, counters)),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	// Stop on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+	if err != nil {
+		fatal("Error listening for gRPC", err)
+	}
+	grpcServer := grpcapi.New(models)
+
+	serverErr := make(chan error, 2)
+	go func() {
+		slog.Info("Server running", "port", cfg.Server.Port)
+		serverErr <- server.ListenAndServe()
+	}()
+	go func() {
+		slog.Info("gRPC server running", "port", cfg.GRPC.Port)
+		serverErr <- grpcServer.Serve(grpcListener)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Server error", "error", err)
 		}
+	case <-ctx.Done():
+		slog.Info("Shutting down")
+	}
+
+	shutdown(server, grpcServer, workers, mongoClient, cfg.Server)
+}
+
+// shutdown drains in-flight requests and calls, stops the background workers and disconnects from Mongo,
+// in that order, all within the configured shutdown timeout
+func shutdown(server *http.Server, grpcServer *grpc.Server, workers *worker.Group, mongoClient *mongo.Client, cfg config.ServerConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("Error draining requests", "error", err)
+	}
+
+	// Open WatchTodos streams never finish by themselves, so stop hard at the deadline
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
 	}()
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+
+	if err := workers.Stop(ctx); err != nil {
+		slog.Error("Error stopping workers", "error", err)
+	}
+
+	if mongoClient != nil {
+		if err := mongoClient.Disconnect(ctx); err != nil {
+			slog.Error("Error disconnecting from mongo", "error", err)
+		}
+	}
+
+	slog.Info("Shutdown complete")
+}
+
+// runMigrations migrates the schema "up" or "down" to target, or logs the "status" of every migration.
+// A negative target migrates up to the latest version, or down by one step.
+func runMigrations(models *services.Models, command string, target int) error {
+	migrator, err := models.NewMigrator()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
 
-	services.New(mongoClient)
+	switch command {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			slog.Info("Migration", "version", status.Version, "description", status.Description, "appliedAt", status.AppliedAt)
+		}
+		return nil
+
+	case "up":
+		applied, err := migrator.Up(ctx, max(target, 0))
+		for _, migration := range applied {
+			slog.Info("Applied migration", "version", migration.Version, "description", migration.Description)
+		}
+		return err
 
-	log.Println("Server running in port", 8080)
-	log.Fatal(http.ListenAndServe(":8080", handlers.CreateRouter()))
+	case "down":
+		reverted, err := migrator.Down(ctx, target)
+		for _, migration := range reverted {
+			slog.Info("Reverted migration", "version", migration.Version, "description", migration.Description)
+		}
+		return err
+	}
+
+	return fmt.Errorf("unknown migrate command %q, want up, down or status", command)
+}
+
+// disconnect closes the Mongo connection, if any, within the configured shutdown timeout
+func disconnect(mongoClient *mongo.Client, cfg config.ServerConfig) {
+	if mongoClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := mongoClient.Disconnect(ctx); err != nil {
+		slog.Error("Error disconnecting from mongo", "error", err)
+	}
+}
 
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,281 @@
+// Package backup reads and writes logical backups: a zip archive holding each collection
+// as NDJSON in MongoDB canonical Extended JSON, with a manifest recording the schema version
+// and a checksum of every file.
+package backup
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FormatVersion is the version of the archive layout written by this package
+const FormatVersion = 1
+
+// manifestFile is the name of the manifest inside the archive
+const manifestFile = "manifest.json"
+
+// ErrInvalid is returned for archives that are corrupt or not backups at all
+var ErrInvalid = errors.New("invalid backup archive")
+
+// Manifest describes the contents of an archive
+type Manifest struct {
+	Format int `json:"format"`
+	// SchemaVersion is the newest schema migration applied to the data when it was exported
+	SchemaVersion int          `json:"schemaVersion"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	Collections   []Collection `json:"collections"`
+}
+
+// Collection is one collection in an archive
+type Collection struct {
+	Name      string `json:"name"`
+	File      string `json:"file"`
+	Documents int64  `json:"documents"`
+	// SHA256 is the hex checksum of the uncompressed NDJSON file
+	SHA256 string `json:"sha256"`
+}
+
+// Writer streams collections into a new archive
+type Writer struct {
+	zw       *zip.Writer
+	manifest Manifest
+	current  *CollectionWriter
+}
+
+// NewWriter starts an archive on w, for data at schemaVersion. Nothing is buffered
+// beyond the current document, so w can be a network connection.
+func NewWriter(w io.Writer, schemaVersion int) *Writer {
+	return &Writer{
+		zw:       zip.NewWriter(w),
+		manifest: Manifest{Format: FormatVersion, SchemaVersion: schemaVersion, CreatedAt: time.Now().UTC()},
+	}
+}
+
+// Collection starts the file of the named collection. Documents are then written to the
+// returned CollectionWriter until the next call to Collection or Close.
+func (w *Writer) Collection(name string) (*CollectionWriter, error) {
+	w.finish()
+
+	file := path.Join("collections", name+".ndjson")
+	out, err := w.zw.Create(file)
+	if err != nil {
+		return nil, err
+	}
+
+	w.manifest.Collections = append(w.manifest.Collections, Collection{Name: name, File: file})
+	w.current = &CollectionWriter{out: out, sum: sha256.New(), info: &w.manifest.Collections[len(w.manifest.Collections)-1]}
+	return w.current, nil
+}
+
+// finish records the count and checksum of the current collection
+func (w *Writer) finish() {
+	if w.current != nil {
+		w.current.info.SHA256 = hex.EncodeToString(w.current.sum.Sum(nil))
+		w.current = nil
+	}
+}
+
+// Close writes the manifest and completes the archive, returning the manifest written
+func (w *Writer) Close() (Manifest, error) {
+	w.finish()
+
+	out, err := w.zw.Create(manifestFile)
+	if err != nil {
+		return Manifest{}, err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(w.manifest); err != nil {
+		return Manifest{}, err
+	}
+
+	return w.manifest, w.zw.Close()
+}
+
+// CollectionWriter writes the documents of one collection
+type CollectionWriter struct {
+	out  io.Writer
+	sum  hash.Hash
+	info *Collection
+}
+
+// Write appends doc as one line of canonical Extended JSON, which keeps ObjectIDs,
+// dates and number types intact
+func (c *CollectionWriter) Write(doc bson.Raw) error {
+	line, err := bson.MarshalExtJSON(doc, true, false)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	c.sum.Write(line)
+	if _, err := c.out.Write(line); err != nil {
+		return err
+	}
+	c.info.Documents++
+	return nil
+}
+
+// Archive is an archive opened for reading
+type Archive struct {
+	Manifest Manifest
+	files    map[string]*zip.File
+	closer   io.Closer
+}
+
+// Open opens the archive at path and reads its manifest
+func Open(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	archive, err := NewArchive(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	archive.closer = f
+	return archive, nil
+}
+
+// NewArchive reads the archive of size bytes in r, checking its manifest
+func NewArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	archive := &Archive{files: map[string]*zip.File{}}
+	for _, f := range zr.File {
+		archive.files[f.Name] = f
+	}
+
+	manifest, ok := archive.files[manifestFile]
+	if !ok {
+		return nil, fmt.Errorf("%w: no %s", ErrInvalid, manifestFile)
+	}
+	rc, err := manifest.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(&archive.Manifest); err != nil {
+		return nil, fmt.Errorf("%w: reading manifest: %v", ErrInvalid, err)
+	}
+
+	if archive.Manifest.Format != FormatVersion {
+		return nil, fmt.Errorf("%w: format version %d, this build reads %d", ErrInvalid, archive.Manifest.Format, FormatVersion)
+	}
+	seen := map[string]bool{}
+	for _, c := range archive.Manifest.Collections {
+		if seen[c.Name] {
+			return nil, fmt.Errorf("%w: collection %s listed twice", ErrInvalid, c.Name)
+		}
+		seen[c.Name] = true
+		if _, ok := archive.files[c.File]; !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalid, c.File)
+		}
+	}
+
+	return archive, nil
+}
+
+// Close releases the file the archive was opened from
+func (a *Archive) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// collection returns the manifest entry of the named collection
+func (a *Archive) collection(name string) (Collection, bool) {
+	for _, c := range a.Manifest.Collections {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Collection{}, false
+}
+
+// Has reports whether the archive holds the named collection
+func (a *Archive) Has(name string) bool {
+	_, ok := a.collection(name)
+	return ok
+}
+
+// Verify reads every collection in full, checking each document parses and the
+// checksums and counts match the manifest
+func (a *Archive) Verify() error {
+	for _, c := range a.Manifest.Collections {
+		if err := a.Documents(c.Name, func(bson.D) error { return nil }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Documents calls fn with each document of the named collection, in the order they were
+// written. It fails if the collection's checksum or count doesn't match the manifest, which
+// is only known at the end, so callers wanting all or nothing should Verify first.
+func (a *Archive) Documents(name string, fn func(doc bson.D) error) error {
+	c, ok := a.collection(name)
+	if !ok {
+		return fmt.Errorf("%w: no collection %s", ErrInvalid, name)
+	}
+	rc, err := a.files[c.File].Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	defer rc.Close()
+
+	sum := sha256.New()
+	r := bufio.NewReader(io.TeeReader(rc, sum))
+	var count int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var doc bson.D
+			if err := bson.UnmarshalExtJSON(line, true, &doc); err != nil {
+				return fmt.Errorf("%w: %s line %d: %v", ErrInvalid, c.File, count+1, err)
+			}
+			count++
+			if err := fn(doc); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalid, c.File, err)
+		}
+	}
+
+	if got := hex.EncodeToString(sum.Sum(nil)); got != c.SHA256 {
+		return fmt.Errorf("%w: %s checksum is %s, the manifest says %s", ErrInvalid, c.File, got, c.SHA256)
+	}
+	if count != c.Documents {
+		return fmt.Errorf("%w: %s has %d documents, the manifest says %d", ErrInvalid, c.File, count, c.Documents)
+	}
+	return nil
+}
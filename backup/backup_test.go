@@ -0,0 +1,144 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// write returns an archive at schema version 3 holding the given documents of each collection
+func write(t *testing.T, collections map[string][]bson.D, order ...string) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf, 3)
+	for _, name := range order {
+		out, err := w.Collection(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, doc := range collections[name] {
+			raw, _ := bson.Marshal(doc)
+			if err := out.Write(raw); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if _, err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	when := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	data := map[string][]bson.D{
+		"users": {
+			{{Key: "_id", Value: id}, {Key: "email", Value: "a@example.com"}, {Key: "volNeeded", Value: int32(3)}},
+			{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "createdAt", Value: primitive.NewDateTimeFromTime(when)}},
+		},
+		"empty": nil,
+	}
+
+	b := write(t, data, "users", "empty")
+	archive, err := NewArchive(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive.Manifest.SchemaVersion != 3 || len(archive.Manifest.Collections) != 2 {
+		t.Fatalf("got manifest %+v", archive.Manifest)
+	}
+	if err := archive.Verify(); err != nil {
+		t.Fatalf("verifying: %v", err)
+	}
+
+	var docs []bson.D
+	archive.Documents("users", func(doc bson.D) error {
+		docs = append(docs, doc)
+		return nil
+	})
+	if len(docs) != 2 {
+		t.Fatalf("got %d users, want 2", len(docs))
+	}
+	// Types survive, not just values
+	if docs[0][0].Value != id || docs[0][2].Value != int32(3) {
+		t.Errorf("got %#v, want the ObjectID and int32 back", docs[0])
+	}
+	if got := docs[1][1].Value; got != primitive.NewDateTimeFromTime(when) {
+		t.Errorf("got date %#v", got)
+	}
+
+	if !archive.Has("empty") || archive.Has("todos") {
+		t.Error("got the wrong collections")
+	}
+}
+
+// rewrite copies the archive b, passing each file's content through edit
+func rewrite(t *testing.T, b []byte, edit func(name string, content []byte) []byte) []byte {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		out, _ := zw.Create(f.Name)
+		out.Write(edit(f.Name, content))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestInvalidArchives(t *testing.T) {
+	good := write(t, map[string][]bson.D{"todos": {{{Key: "_id", Value: "1"}, {Key: "task", Value: "Dog walking"}}}}, "todos")
+
+	tests := []struct {
+		name string
+		edit func(name string, content []byte) []byte
+	}{
+		{"tampered document", func(name string, content []byte) []byte {
+			return bytes.Replace(content, []byte("Dog"), []byte("Cat"), 1)
+		}},
+		{"dropped document", func(name string, content []byte) []byte {
+			if name == manifestFile {
+				return content
+			}
+			return nil
+		}},
+		{"newer format", func(name string, content []byte) []byte {
+			return bytes.Replace(content, []byte(`"format": 1`), []byte(`"format": 2`), 1)
+		}},
+		{"missing file", func(name string, content []byte) []byte {
+			return bytes.Replace(content, []byte("todos.ndjson"), []byte("other.ndjson"), 1)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := rewrite(t, good, tt.edit)
+			archive, err := NewArchive(bytes.NewReader(b), int64(len(b)))
+			if err == nil {
+				err = archive.Verify()
+			}
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("got %v, want ErrInvalid", err)
+			}
+		})
+	}
+
+	if _, err := NewArchive(bytes.NewReader([]byte("not a zip")), 9); !errors.Is(err, ErrInvalid) {
+		t.Errorf("got %v for a file that isn't a zip, want ErrInvalid", err)
+	}
+}
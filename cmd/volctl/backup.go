@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/volunteerService-backend/backup"
+)
+
+func (c *cli) backupExport(ctx context.Context, args []string) error {
+	fs := c.flags("backup export")
+	out := fs.String("out", "", "file to write the archive to, - for standard output (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("out", *out); err != nil {
+		return err
+	}
+
+	if *out == "-" {
+		_, err := c.models.ExportBackup(ctx, c.out)
+		return err
+	}
+
+	// The archive only takes the final name once complete, so a failed export never
+	// leaves behind something that looks like a backup
+	partial := *out + ".partial"
+	f, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+	manifest, err := c.models.ExportBackup(ctx, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(partial, *out)
+	}
+	if err != nil {
+		os.Remove(partial)
+		return err
+	}
+	return c.printManifest(manifest)
+}
+
+func (c *cli) backupVerify(ctx context.Context, args []string) error {
+	fs := c.flags("backup verify")
+	in := fs.String("in", "", "archive to check (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("in", *in); err != nil {
+		return err
+	}
+
+	archive, err := backup.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	if err := archive.Verify(); err != nil {
+		return err
+	}
+	return c.printManifest(archive.Manifest)
+}
+
+func (c *cli) backupRestore(ctx context.Context, args []string) error {
+	fs := c.flags("backup restore")
+	in := fs.String("in", "", "archive to restore (required)")
+	org := fs.String("org", "", "only restore the data of this organisation")
+	yes := fs.Bool("yes", false, "really restore, rather than only check the archive")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("in", *in); err != nil {
+		return err
+	}
+
+	archive, err := backup.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	if !*yes {
+		if err := archive.Verify(); err != nil {
+			return err
+		}
+		if err := c.printManifest(archive.Manifest); err != nil {
+			return err
+		}
+		if c.format == formatTable {
+			target := "every collection, replacing the current data"
+			if *org != "" {
+				target = "the data of " + *org
+			}
+			_, err := fmt.Fprintf(c.out, "The archive is intact. Run again with -yes to restore %s\n", target)
+			return err
+		}
+		return nil
+	}
+
+	result, err := c.models.RestoreBackup(ctx, archive, *org)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(result.Documents))
+	for name := range result.Documents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name, strconv.FormatInt(result.Documents[name], 10)})
+	}
+	if err := c.print(result, []string{"COLLECTION", "RESTORED"}, rows); err != nil {
+		return err
+	}
+	if c.format == formatTable && len(result.Migrated) > 0 {
+		_, err := fmt.Fprintf(c.out, "Migrated from schema version %d by applying %v\n", result.SchemaVersion, result.Migrated)
+		return err
+	}
+	return nil
+}
+
+// printManifest prints the collections of an archive
+func (c *cli) printManifest(manifest backup.Manifest) error {
+	rows := make([][]string, 0, len(manifest.Collections))
+	for _, collection := range manifest.Collections {
+		rows = append(rows, []string{collection.Name, strconv.FormatInt(collection.Documents, 10), collection.SHA256})
+	}
+	return c.print(manifest, []string{"COLLECTION", "DOCUMENTS", "SHA256"}, rows)
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/volunteerService-backend/migrations"
+	"github.com/volunteerService-backend/services"
+)
+
+// cli runs commands against the services, printing their results to out
+type cli struct {
+	out    io.Writer
+	format string
+	models *services.Models
+}
+
+func newCLI(out io.Writer, format string, models *services.Models) *cli {
+	return &cli{out: out, format: format, models: models}
+}
+
+// command is a subcommand, named by one or two words such as "seed" or "user deactivate"
+type command struct {
+	name    string
+	summary string
+	run     func(c *cli, ctx context.Context, args []string) error
+}
+
+// commands is every command volctl knows, in the order they are listed in the usage
+var commands = []command{
+	{"seed", "create demo organisations, volunteers and todos", (*cli).seed},
+	{"admin create", "sign up a new admin user", (*cli).adminCreate},
+	{"admin promote", "give an existing user the admin role", (*cli).adminPromote},
+	{"admin demote", "take the admin role away from a user", (*cli).adminDemote},
+	{"user reset-password", "set a new password for a user", (*cli).userResetPassword},
+	{"user deactivate", "stop a user from logging in", (*cli).userDeactivate},
+	{"user reactivate", "let a deactivated user log in again", (*cli).userReactivate},
+	{"migrate up", "apply pending schema migrations", (*cli).migrateUp},
+	{"migrate down", "revert schema migrations", (*cli).migrateDown},
+	{"migrate status", "list the schema migrations and when they were applied", (*cli).migrateStatus},
+	{"todos list", "list the todos of an organisation", (*cli).todosList},
+	{"todos purge", "delete every todo of an organisation", (*cli).todosPurge},
+	{"backup export", "write every collection to a backup archive", (*cli).backupExport},
+	{"backup verify", "check the checksums of a backup archive", (*cli).backupVerify},
+	{"backup restore", "restore a backup archive, or one organisation from it", (*cli).backupRestore},
+}
+
+// execute finds the command named by the first words of args and runs it with the rest
+func (c *cli) execute(ctx context.Context, args []string) error {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd.run(c, ctx, args[len(words):])
+		}
+	}
+
+	if len(args) == 0 {
+		return errors.New("no command given, run volctl -h for the list")
+	}
+	return fmt.Errorf("unknown command %q, run volctl -h for the list", strings.Join(args, " "))
+}
+
+// flags returns the flag set of the command name, which prints its usage to the CLI's output
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("volctl "+name, flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	return fs
+}
+
+// requireFlag fails when a required flag was left empty
+func requireFlag(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("-%s is required", name)
+	}
+	return nil
+}
+
+func (c *cli) adminCreate(ctx context.Context, args []string) error {
+	fs := c.flags("admin create")
+	user := services.User{}
+	fs.StringVar(&user.Email, "email", "", "email address to log in with (required)")
+	fs.StringVar(&user.Password, "password", "", "password, generated and printed when empty")
+	fs.StringVar(&user.FirstName, "first", "", "first name")
+	fs.StringVar(&user.LastName, "last", "", "last name")
+	fs.StringVar(&user.ContactNumber, "contact", "", "E.164 contact number (required)")
+	fs.StringVar(&user.UserType, "type", "volunteer", "user type, "+strings.Join(services.UserTypes, " or "))
+	fs.StringVar(&user.OrganisationName, "org", "", "organisation name, for organisation users")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("email", user.Email); err != nil {
+		return err
+	}
+
+	generated := user.Password == ""
+	if generated {
+		user.Password = generatePassword()
+	}
+
+	created, err := c.models.CreateAdmin(ctx, user)
+	if err != nil {
+		return err
+	}
+	if !generated {
+		return c.printUsers(created)
+	}
+
+	// Shown once, for the operator to pass on
+	if c.format == formatJSON {
+		return c.print(struct {
+			User     services.User `json:"user"`
+			Password string        `json:"password"`
+		}{withoutPassword(created), user.Password}, nil, nil)
+	}
+	if err := c.printUsers(created); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.out, "Generated password: %s\n", user.Password)
+	return err
+}
+
+func (c *cli) adminPromote(ctx context.Context, args []string) error {
+	return c.updateUser(ctx, "admin promote", args, func(email string) (services.User, error) {
+		return c.models.SetAdmin(ctx, email, true)
+	})
+}
+
+func (c *cli) adminDemote(ctx context.Context, args []string) error {
+	return c.updateUser(ctx, "admin demote", args, func(email string) (services.User, error) {
+		return c.models.SetAdmin(ctx, email, false)
+	})
+}
+
+func (c *cli) userDeactivate(ctx context.Context, args []string) error {
+	return c.updateUser(ctx, "user deactivate", args, func(email string) (services.User, error) {
+		return c.models.SetDeactivated(ctx, email, true)
+	})
+}
+
+func (c *cli) userReactivate(ctx context.Context, args []string) error {
+	return c.updateUser(ctx, "user reactivate", args, func(email string) (services.User, error) {
+		return c.models.SetDeactivated(ctx, email, false)
+	})
+}
+
+// updateUser runs a command that takes only -email, applies update to that user and prints them
+func (c *cli) updateUser(ctx context.Context, name string, args []string, update func(email string) (services.User, error)) error {
+	fs := c.flags(name)
+	email := fs.String("email", "", "email address of the user (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("email", *email); err != nil {
+		return err
+	}
+
+	user, err := update(*email)
+	if err != nil {
+		return err
+	}
+	return c.printUsers(user)
+}
+
+func (c *cli) userResetPassword(ctx context.Context, args []string) error {
+	fs := c.flags("user reset-password")
+	email := fs.String("email", "", "email address of the user (required)")
+	password := fs.String("password", "", "new password, generated and printed when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("email", *email); err != nil {
+		return err
+	}
+
+	generated := *password == ""
+	if generated {
+		*password = generatePassword()
+	}
+	if err := c.models.ResetPassword(ctx, *email, *password); err != nil {
+		return err
+	}
+
+	result := map[string]string{"email": *email}
+	if generated {
+		result["password"] = *password
+	}
+	return c.print(result, []string{"EMAIL", "PASSWORD"}, [][]string{{*email, result["password"]}})
+}
+
+// generatePassword returns a random password that passes the signup rules
+func generatePassword() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	// The suffix guarantees the upper case, lower case and digit the rules ask for
+	return base64.RawURLEncoding.EncodeToString(b) + "Aa1"
+}
+
+// migrationFlags parses the -to flag of the migrate commands
+func (c *cli) migrationFlags(name, help string, args []string) (int, error) {
+	fs := c.flags(name)
+	to := fs.Int("to", -1, help)
+	if err := fs.Parse(args); err != nil {
+		return 0, err
+	}
+	return *to, nil
+}
+
+func (c *cli) migrateUp(ctx context.Context, args []string) error {
+	to, err := c.migrationFlags("migrate up", "version to migrate up to (defaults to the latest)", args)
+	if err != nil {
+		return err
+	}
+	migrator, err := c.models.NewMigrator()
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx, max(to, 0))
+	if printErr := c.printMigrations(applied); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func (c *cli) migrateDown(ctx context.Context, args []string) error {
+	to, err := c.migrationFlags("migrate down", "version to migrate down to (defaults to one step back)", args)
+	if err != nil {
+		return err
+	}
+	migrator, err := c.models.NewMigrator()
+	if err != nil {
+		return err
+	}
+
+	reverted, err := migrator.Down(ctx, to)
+	if printErr := c.printMigrations(reverted); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func (c *cli) migrateStatus(ctx context.Context, args []string) error {
+	if err := c.flags("migrate status").Parse(args); err != nil {
+		return err
+	}
+	migrator, err := c.models.NewMigrator()
+	if err != nil {
+		return err
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(statuses))
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = status.AppliedAt.Format(time.RFC3339)
+		}
+		rows = append(rows, []string{strconv.Itoa(status.Version), status.Description, applied})
+	}
+	return c.print(statuses, []string{"VERSION", "DESCRIPTION", "APPLIED"}, rows)
+}
+
+// printMigrations prints the migrations a migrate command applied or reverted
+func (c *cli) printMigrations(done []migrations.Migration) error {
+	type result struct {
+		Version     int    `json:"version"`
+		Description string `json:"description"`
+	}
+
+	results := make([]result, 0, len(done))
+	rows := make([][]string, 0, len(done))
+	for _, migration := range done {
+		results = append(results, result{migration.Version, migration.Description})
+		rows = append(rows, []string{strconv.Itoa(migration.Version), migration.Description})
+	}
+	return c.print(results, []string{"VERSION", "DESCRIPTION"}, rows)
+}
+
+// orgFlag parses the -org flag shared by the todos commands, and -yes when yes isn't nil
+func (c *cli) orgFlag(name string, args []string, yes *bool) (string, error) {
+	fs := c.flags(name)
+	org := fs.String("org", "", "organisation name (required)")
+	if yes != nil {
+		fs.BoolVar(yes, "yes", false, "really delete, rather than only count what would be deleted")
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	return *org, requireFlag("org", *org)
+}
+
+func (c *cli) todosList(ctx context.Context, args []string) error {
+	org, err := c.orgFlag("todos list", args, nil)
+	if err != nil {
+		return err
+	}
+
+	todos, err := c.models.GetTodosByOrg(ctx, org)
+	if err != nil {
+		return err
+	}
+	return c.printTodos(todos)
+}
+
+func (c *cli) todosPurge(ctx context.Context, args []string) error {
+	var yes bool
+	org, err := c.orgFlag("todos purge", args, &yes)
+	if err != nil {
+		return err
+	}
+
+	result := struct {
+		Organisation string `json:"orgName"`
+		Deleted      int    `json:"deleted"`
+		DryRun       bool   `json:"dryRun"`
+	}{Organisation: org, DryRun: !yes}
+
+	if yes {
+		result.Deleted, err = c.models.PurgeTodosByOrg(ctx, org, actor)
+	} else {
+		var todos []services.Todo
+		todos, err = c.models.GetTodosByOrg(ctx, org)
+		result.Deleted = len(todos)
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun && c.format == formatTable {
+		_, err := fmt.Fprintf(c.out, "Would delete %d todos of %s, run again with -yes to delete them\n", result.Deleted, org)
+		return err
+	}
+	return c.print(result, []string{"ORGANISATION", "DELETED"}, [][]string{{org, strconv.Itoa(result.Deleted)}})
+}
@@ -0,0 +1,93 @@
+// Command volctl is the operators' tool for the volunteer service. It seeds demo data,
+// manages admins and accounts, runs migrations, maintains todos and takes and restores
+// backups, going through the same services layer as the API.
+//
+//	volctl [-config file] [-o table|json] <command> [flags]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/db"
+	"github.com/volunteerService-backend/services"
+)
+
+// actor is recorded in the history of the todos volctl changes
+const actor = "volctl"
+
+func main() {
+	// Logs go to stderr so the output can be piped; only problems are worth showing
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "volctl:", err)
+		os.Exit(1)
+	}
+}
+
+// run loads the configuration, connects to the configured storage and runs the command in args
+func run(ctx context.Context, args []string, out io.Writer) error {
+	global := flag.NewFlagSet("volctl", flag.ContinueOnError)
+	configFile := global.String("config", "", "path to a YAML config file (defaults to $CONFIG_FILE)")
+	format := global.String("o", formatTable, "output format, table or json")
+	global.Usage = func() { usage(global) }
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+	if *format != formatTable && *format != formatJSON {
+		return fmt.Errorf("unknown output format %q, want table or json", *format)
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Storage.Backend == config.StorageMongo {
+		mongoClient, err := db.ConnectToMongo(cfg.Mongo)
+		if err != nil {
+			return fmt.Errorf("connecting to mongo: %w", err)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+			defer cancel()
+			mongoClient.Disconnect(ctx)
+		}()
+		models := services.New(mongoClient, cfg)
+		return newCLI(out, *format, models).execute(ctx, global.Args())
+	}
+
+	slog.Warn("Using the in-memory storage backend, nothing volctl does will be kept")
+	models := services.New(nil, cfg)
+	return newCLI(out, *format, models).execute(ctx, global.Args())
+}
+
+// usage lists the global flags and every command
+func usage(global *flag.FlagSet) {
+	w := global.Output()
+	fmt.Fprintln(w, "Usage: volctl [-config file] [-o table|json] <command> [flags]")
+	fmt.Fprintln(w)
+	global.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-20s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run volctl <command> -h for the flags of a command.")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/volunteerService-backend/services"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// print writes value as indented JSON, or rows under headers as an aligned table
+func (c *cli) print(value interface{}, headers []string, rows [][]string) error {
+	if c.format == formatJSON {
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(c.out, "Nothing found")
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// withoutPassword returns user without the password hash, which is never printed
+func withoutPassword(user services.User) services.User {
+	user.Password = ""
+	return user
+}
+
+// printUsers prints the users' account details
+func (c *cli) printUsers(users ...services.User) error {
+	rows := make([][]string, 0, len(users))
+	for i, user := range users {
+		users[i] = withoutPassword(user)
+
+		name := user.DisplayName()
+		if user.UserType == "organisation" {
+			name = user.OrganisationName
+		}
+		deactivated := ""
+		if user.DeactivatedAt != nil {
+			deactivated = user.DeactivatedAt.Format(time.RFC3339)
+		}
+		rows = append(rows, []string{user.ID, user.Email, name, user.UserType, user.Role, deactivated})
+	}
+	return c.print(users, []string{"ID", "EMAIL", "NAME", "TYPE", "ROLE", "DEACTIVATED"}, rows)
+}
+
+// printTodos prints the todos with how many of the volunteers needed have joined
+func (c *cli) printTodos(todos []services.Todo) error {
+	rows := make([][]string, 0, len(todos))
+	for _, todo := range todos {
+		volunteers := strconv.Itoa(len(todo.Volunteer))
+		if todo.VolunteersNeeded > 0 {
+			volunteers += "/" + strconv.Itoa(todo.VolunteersNeeded)
+		}
+		rows = append(rows, []string{
+			todo.ID,
+			todo.Task,
+			todo.OrganisationName,
+			todo.VolunteerType,
+			todo.Time.Format(time.RFC3339),
+			volunteers,
+			strconv.FormatBool(todo.Completed),
+		})
+	}
+	return c.print(todos, []string{"ID", "TASK", "ORGANISATION", "VOLTYPE", "TIME", "VOLUNTEERS", "COMPLETED"}, rows)
+}
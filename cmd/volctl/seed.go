@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/volunteerService-backend/services"
+)
+
+// The demo data is drawn from these, so it looks like the real thing in the frontend
+var (
+	demoOrganisations = []struct{ name, orgType string }{
+		{"Greenfield Community Trust", "community"},
+		{"Riverside Food Bank", "charity"},
+		{"Northgate Animal Rescue", "charity"},
+		{"St Mary's Parish", "religious"},
+		{"Oakwood Primary School", "educational"},
+		{"Harbour Health Clinic", "healthcare"},
+		{"City Council Parks Team", "government"},
+		{"Open Code Collective", "ngo"},
+	}
+	demoFirstNames = []string{"Amara", "Ben", "Chloe", "Dev", "Ella", "Finn", "Grace", "Hamza", "Isla", "Jonah", "Kofi", "Lucy", "Maya", "Noah", "Olivia", "Priya", "Rory", "Sofia", "Tom", "Zara"}
+	demoLastNames  = []string{"Adams", "Begum", "Clarke", "Davies", "Evans", "Fraser", "Green", "Hughes", "Iqbal", "Jones", "Khan", "Lewis", "Murphy", "Nowak", "Okafor", "Patel", "Reid", "Smith", "Taylor", "Walsh"}
+	demoTasks      = []struct{ task, description, volType string }{
+		{"Sort food donations", "Check dates and sort tins, dry goods and toiletries into crates.", "community"},
+		{"Litter pick in the park", "Gloves, bags and grabbers are provided. Meet at the main gate.", "environment"},
+		{"Reading buddies", "Listen to year 2 pupils read and help with tricky words.", "education"},
+		{"Dog walking", "Walk two of our long-stay dogs around the fields.", "animal-welfare"},
+		{"Clinic reception cover", "Greet patients and help them check in.", "healthcare"},
+		{"Fix the volunteer website", "Help us move the site to a static generator.", "technology"},
+		{"Tree planting", "Plant saplings along the new cycle path.", "environment"},
+		{"Flood kit packing", "Pack emergency kits for households on the flood plain.", "disaster-relief"},
+		{"Community lunch", "Cook and serve a hot lunch for up to 60 neighbours.", "general"},
+		{"Homework club", "Support secondary pupils with maths homework.", "education"},
+	}
+)
+
+// seedResult counts what seeding created
+type seedResult struct {
+	Organisations int `json:"organisations"`
+	Volunteers    int `json:"volunteers"`
+	Todos         int `json:"todos"`
+	Joins         int `json:"joins"`
+}
+
+func (c *cli) seed(ctx context.Context, args []string) error {
+	fs := c.flags("seed")
+	orgs := fs.Int("orgs", 4, "number of organisations, at most "+strconv.Itoa(len(demoOrganisations)))
+	volunteers := fs.Int("volunteers", 20, "number of volunteers")
+	todos := fs.Int("todos", 5, "number of todos per organisation")
+	password := fs.String("password", "Demo1234", "password of every demo account")
+	seed := fs.Int64("seed", 1, "random seed, the same seed creates the same data")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *orgs < 0 || *orgs > len(demoOrganisations) || *volunteers < 0 || *volunteers > 500 || *todos < 0 {
+		return fmt.Errorf("-orgs must be 0 to %d, -volunteers 0 to 500 and -todos at least 0", len(demoOrganisations))
+	}
+
+	random := rand.New(rand.NewSource(*seed))
+	var result seedResult
+
+	// Accounts use reserved example domains and drama phone numbers, and are reused
+	// when seeding again, so they never reach anyone real
+	var organisations []services.User
+	for i := 0; i < *orgs; i++ {
+		org := demoOrganisations[i]
+		user, created, err := c.findOrSignup(ctx, services.User{
+			Email:            "hello@" + slug(org.name) + ".example.org",
+			Password:         *password,
+			ContactNumber:    fmt.Sprintf("+447700900%03d", i),
+			UserType:         "organisation",
+			OrganisationName: org.name,
+			OrganisationType: org.orgType,
+		})
+		if err != nil {
+			return fmt.Errorf("seeding %s: %w", org.name, err)
+		}
+		if created {
+			result.Organisations++
+		}
+		organisations = append(organisations, user)
+	}
+
+	var people []services.User
+	for i := 0; i < *volunteers; i++ {
+		first := demoFirstNames[random.Intn(len(demoFirstNames))]
+		last := demoLastNames[random.Intn(len(demoLastNames))]
+		user, created, err := c.findOrSignup(ctx, services.User{
+			FirstName:     first,
+			LastName:      last,
+			Email:         fmt.Sprintf("volunteer%d@example.com", i+1),
+			Password:      *password,
+			ContactNumber: fmt.Sprintf("+447700900%03d", 500+i),
+			UserType:      "volunteer",
+			VolunteerType: services.VolunteerTypes[random.Intn(len(services.VolunteerTypes))],
+		})
+		if err != nil {
+			return fmt.Errorf("seeding volunteer %d: %w", i+1, err)
+		}
+		if created {
+			result.Volunteers++
+		}
+		people = append(people, user)
+	}
+
+	// Todos start on the hour over the next four weeks, during the day
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, org := range organisations {
+		for i := 0; i < *todos; i++ {
+			task := demoTasks[random.Intn(len(demoTasks))]
+			start := today.Add(time.Duration(1+random.Intn(28))*24*time.Hour + time.Duration(9+random.Intn(8))*time.Hour)
+			todo := services.Todo{
+				Task:             task.task,
+				Description:      task.description,
+				OrganisationName: org.OrganisationName,
+				OrganisationType: org.OrganisationType,
+				VolunteerType:    task.volType,
+				Time:             start,
+				EndTime:          start.Add(time.Duration(1+random.Intn(3)) * time.Hour),
+				VolunteersNeeded: 1 + random.Intn(6),
+			}
+			id, err := c.models.InsertTodo(ctx, todo, actor)
+			if err != nil {
+				return fmt.Errorf("seeding todo: %w", err)
+			}
+			todo.ID = id
+			result.Todos++
+
+			joins, err := c.seedJoins(ctx, random, todo, people)
+			if err != nil {
+				return err
+			}
+			result.Joins += joins
+		}
+	}
+
+	return c.print(result, []string{"ORGANISATIONS", "VOLUNTEERS", "TODOS", "JOINS"}, [][]string{{
+		strconv.Itoa(result.Organisations), strconv.Itoa(result.Volunteers), strconv.Itoa(result.Todos), strconv.Itoa(result.Joins),
+	}})
+}
+
+// seedJoins signs random volunteers up for up to all the places of todo, skipping anyone
+// it would double book, and returns how many joined
+func (c *cli) seedJoins(ctx context.Context, random *rand.Rand, todo services.Todo, people []services.User) (int, error) {
+	want, joins := random.Intn(todo.VolunteersNeeded+1), 0
+	for _, i := range random.Perm(len(people)) {
+		if joins >= want {
+			break
+		}
+
+		person := people[i]
+		conflicts, err := c.models.CheckSchedule(ctx, todo, person.ID)
+		if err != nil {
+			return joins, err
+		}
+		if len(conflicts) > 0 {
+			continue
+		}
+
+		volunteer := services.Volunteer{VolunteerID: person.ID, VolunteerName: person.DisplayName()}
+		if err := c.models.JoinTodo(ctx, todo.ID, volunteer, actor); err != nil {
+			return joins, fmt.Errorf("seeding join: %w", err)
+		}
+		joins++
+	}
+
+	return joins, nil
+}
+
+// findOrSignup signs user up, or returns the existing user with the same email.
+// created reports whether the user is new.
+func (c *cli) findOrSignup(ctx context.Context, user services.User) (services.User, bool, error) {
+	_, err := c.models.Signup(ctx, user)
+	if err != nil && !errors.Is(err, services.ErrConflict) {
+		return services.User{}, false, err
+	}
+
+	existing, lookupErr := c.models.GetUserByEmail(ctx, user.Email)
+	if lookupErr != nil {
+		return services.User{}, false, lookupErr
+	}
+	return existing, err == nil, nil
+}
+
+// slug turns a name into a lower case domain label, e.g. "St Mary's Parish" into "stmarysparish"
+func slug(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return -1
+	}, name)
+}
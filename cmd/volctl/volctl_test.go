@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/services"
+)
+
+// newTestCLI returns a CLI over an empty in-memory store, printing in format
+func newTestCLI(t *testing.T, format string) (*cli, *bytes.Buffer) {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Storage.Backend = config.StorageMemory
+	cfg.Security.JWTSecret = "volctl-test-secret-at-least-32-bytes"
+
+	out := &bytes.Buffer{}
+	return newCLI(out, format, services.New(nil, cfg)), out
+}
+
+// mustRun runs the command line args, failing the test on error, and returns its output
+func mustRun(t *testing.T, c *cli, out *bytes.Buffer, args ...string) string {
+	t.Helper()
+
+	out.Reset()
+	if err := c.execute(context.Background(), args); err != nil {
+		t.Fatalf("volctl %s: %v", strings.Join(args, " "), err)
+	}
+	return out.String()
+}
+
+func TestSeedAndTodos(t *testing.T) {
+	c, out := newTestCLI(t, formatJSON)
+
+	var seeded seedResult
+	json.Unmarshal([]byte(mustRun(t, c, out, "seed", "-orgs", "2", "-volunteers", "4", "-todos", "3")), &seeded)
+	if seeded.Organisations != 2 || seeded.Volunteers != 4 || seeded.Todos != 6 {
+		t.Fatalf("got %+v, want 2 organisations, 4 volunteers and 6 todos", seeded)
+	}
+
+	// Seeding again reuses the accounts
+	json.Unmarshal([]byte(mustRun(t, c, out, "seed", "-orgs", "2", "-volunteers", "4", "-todos", "1")), &seeded)
+	if seeded.Organisations != 0 || seeded.Volunteers != 0 || seeded.Todos != 2 {
+		t.Errorf("got %+v seeding again, want only new todos", seeded)
+	}
+
+	org := demoOrganisations[0].name
+	var todos []services.Todo
+	json.Unmarshal([]byte(mustRun(t, c, out, "todos", "list", "-org", org)), &todos)
+	if len(todos) != 4 {
+		t.Fatalf("got %d todos of %s, want 4", len(todos), org)
+	}
+
+	// Purging needs -yes, without it only counts
+	mustRun(t, c, out, "todos", "purge", "-org", org)
+	mustRun(t, c, out, "todos", "purge", "-org", org, "-yes")
+	if !strings.Contains(out.String(), `"deleted": 4`) {
+		t.Errorf("got %s, want 4 deleted", out)
+	}
+	if got := mustRun(t, c, out, "todos", "list", "-org", org); strings.TrimSpace(got) != "[]" {
+		t.Errorf("got %s after purging", got)
+	}
+}
+
+func TestUserCommands(t *testing.T) {
+	ctx := context.Background()
+	c, out := newTestCLI(t, formatTable)
+
+	got := mustRun(t, c, out, "admin", "create", "-email", "ops@example.com", "-first", "Ops", "-contact", "+447700900999")
+	if !strings.Contains(got, "admin") || !strings.Contains(got, "Generated password: ") {
+		t.Fatalf("got %q, want the new admin and their password", got)
+	}
+	if user, _ := c.models.GetUserByEmail(ctx, "ops@example.com"); !user.IsAdmin() {
+		t.Errorf("got role %q, want admin", user.Role)
+	}
+
+	mustRun(t, c, out, "admin", "demote", "-email", "ops@example.com")
+	if user, _ := c.models.GetUserByEmail(ctx, "ops@example.com"); user.IsAdmin() {
+		t.Error("got an admin after demoting")
+	}
+
+	mustRun(t, c, out, "user", "deactivate", "-email", "ops@example.com")
+	if user, _ := c.models.GetUserByEmail(ctx, "ops@example.com"); user.DeactivatedAt == nil {
+		t.Error("got an active user after deactivating")
+	}
+	mustRun(t, c, out, "user", "reactivate", "-email", "ops@example.com")
+	if user, _ := c.models.GetUserByEmail(ctx, "ops@example.com"); user.DeactivatedAt != nil {
+		t.Error("got a deactivated user after reactivating")
+	}
+
+	mustRun(t, c, out, "user", "reset-password", "-email", "ops@example.com", "-password", "NewPassw0rd")
+	err := c.execute(ctx, []string{"user", "reset-password", "-email", "ops@example.com", "-password", "weak"})
+	if !errors.Is(err, services.ErrValidation) {
+		t.Errorf("got %v for a weak password, want a validation error", err)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	c, _ := newTestCLI(t, formatTable)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		args []string
+		want error
+	}{
+		{"unknown command", []string{"frobnicate"}, nil},
+		{"missing flag", []string{"user", "deactivate"}, nil},
+		{"unknown user", []string{"admin", "promote", "-email", "nobody@example.com"}, services.ErrNotFound},
+		{"migrations need mongo", []string{"migrate", "status"}, services.ErrUnsupported},
+		{"backups need mongo", []string{"backup", "export", "-out", "-"}, services.ErrUnsupported},
+		{"missing archive", []string{"backup", "verify", "-in", "testdata/missing.zip"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.execute(ctx, tt.args)
+			if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,485 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting the API needs to start
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	GRPC     GRPCConfig     `yaml:"grpc"`
+	Mongo    MongoConfig    `yaml:"mongo"`
+	Security SecurityConfig `yaml:"security"`
+	Storage  StorageConfig  `yaml:"storage"`
+}
+
+// ServerConfig holds the HTTP server settings
+type ServerConfig struct {
+	Port            int             `yaml:"port"`
+	AllowedOrigins  []string        `yaml:"allowedOrigins"`
+	ReadTimeout     time.Duration   `yaml:"readTimeout"`
+	WriteTimeout    time.Duration   `yaml:"writeTimeout"`
+	IdleTimeout     time.Duration   `yaml:"idleTimeout"`
+	ShutdownTimeout time.Duration   `yaml:"shutdownTimeout"`
+	GraphQL         GraphQLConfig   `yaml:"graphql"`
+	RateLimit       RateLimitConfig `yaml:"rateLimit"`
+}
+
+// GraphQLConfig limits the cost of the queries accepted by the GraphQL endpoint
+type GraphQLConfig struct {
+	// MaxDepth is the deepest nesting of fields a query may select
+	MaxDepth int `yaml:"maxDepth"`
+	// MaxComplexity is the highest estimated cost of a query, counting each field once
+	// and the fields below a list once per expected item
+	MaxComplexity int `yaml:"maxComplexity"`
+}
+
+// Rate limit stores and keys
+const (
+	RateLimitStoreMemory = "memory"
+	RateLimitStoreMongo  = "mongo"
+
+	RateLimitByIP    = "ip"
+	RateLimitByUser  = "user"
+	RateLimitByGroup = "group"
+)
+
+// RateLimitConfig throttles clients with token buckets and guards login against brute force
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Store keeps the counters, "memory" for each instance on its own or "mongo" to share them
+	Store string `yaml:"store"`
+	// API applies to every route
+	API RateLimit `yaml:"api"`
+	// Auth applies to login and signup, on top of API
+	Auth RateLimit `yaml:"auth"`
+	// Login slows down and then locks out repeated failed logins for the same email
+	Login LoginLimitConfig `yaml:"login"`
+}
+
+// RateLimit is a token bucket holding up to Burst requests, refilled at Requests every Per
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+	// Key is who shares a bucket: each "ip", each "user" (by IP when anonymous),
+	// or the whole route "group"
+	Key string `yaml:"key"`
+}
+
+// LoginLimitConfig sets how failed logins for an email are throttled
+type LoginLimitConfig struct {
+	// Delay is the wait after the first failure, doubling with each further failure up to MaxDelay
+	Delay    time.Duration `yaml:"delay"`
+	MaxDelay time.Duration `yaml:"maxDelay"`
+	// MaxFailures in a row lock the email out for Lockout
+	MaxFailures int           `yaml:"maxFailures"`
+	Lockout     time.Duration `yaml:"lockout"`
+}
+
+// GRPCConfig holds the gRPC server settings
+type GRPCConfig struct {
+	Port int `yaml:"port"`
+}
+
+// MongoConfig holds the MongoDB connection settings
+type MongoConfig struct {
+	URI      string        `yaml:"uri"`
+	Database string        `yaml:"database"`
+	Username string        `yaml:"username"`
+	Password string        `yaml:"password"`
+	Timeouts MongoTimeouts `yaml:"timeouts"`
+	// AutoMigrate applies pending schema migrations at startup. Turn it off to run them
+	// from the command line instead, before rolling out a new version.
+	AutoMigrate bool `yaml:"autoMigrate"`
+}
+
+// MongoTimeouts bound how long each kind of database operation may take
+type MongoTimeouts struct {
+	// Connect bounds connecting and picking a server for each operation
+	Connect time.Duration `yaml:"connect"`
+	Read    time.Duration `yaml:"read"`
+	Write   time.Duration `yaml:"write"`
+	// Aggregate bounds the analytics aggregations and maintenance scans
+	Aggregate time.Duration `yaml:"aggregate"`
+}
+
+// SecurityConfig holds the settings used to issue auth tokens
+type SecurityConfig struct {
+	JWTSecret string        `yaml:"jwtSecret"`
+	TokenTTL  time.Duration `yaml:"tokenTTL"`
+	// CookieSameSite is the SameSite attribute of the auth cookies: "lax", "strict",
+	// or "none" for a frontend on another site
+	CookieSameSite string `yaml:"cookieSameSite"`
+}
+
+// SameSite values for the auth cookies
+const (
+	SameSiteLax    = "lax"
+	SameSiteStrict = "strict"
+	SameSiteNone   = "none"
+)
+
+// Storage backends
+const (
+	StorageMongo  = "mongo"
+	StorageMemory = "memory"
+)
+
+// StorageConfig selects where the API keeps its data
+type StorageConfig struct {
+	// Backend is "mongo", or "memory" to run without a database for local development and tests
+	Backend string `yaml:"backend"`
+}
+
+// minJWTSecretLength is the shortest JWT secret accepted, 256 bits for HS256
+const minJWTSecretLength = 32
+
+// Default returns the configuration used for anything not set in the file or environment
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            8080,
+			AllowedOrigins:  []string{"http://localhost:3000"},
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 20 * time.Second,
+			GraphQL: GraphQLConfig{
+				MaxDepth:      8,
+				MaxComplexity: 1000,
+			},
+			RateLimit: RateLimitConfig{
+				Enabled: true,
+				Store:   RateLimitStoreMemory,
+				API:     RateLimit{Requests: 600, Per: time.Minute, Burst: 120, Key: RateLimitByUser},
+				Auth:    RateLimit{Requests: 20, Per: time.Minute, Burst: 20, Key: RateLimitByIP},
+				Login: LoginLimitConfig{
+					Delay:       time.Second,
+					MaxDelay:    30 * time.Second,
+					MaxFailures: 5,
+					Lockout:     15 * time.Minute,
+				},
+			},
+		},
+		GRPC: GRPCConfig{
+			Port: 9090,
+		},
+		Mongo: MongoConfig{
+			URI:      "mongodb://localhost:27017",
+			Database: "volunteerService-backend-db",
+			Timeouts: MongoTimeouts{
+				Connect:   10 * time.Second,
+				Read:      5 * time.Second,
+				Write:     5 * time.Second,
+				Aggregate: 30 * time.Second,
+			},
+			AutoMigrate: true,
+		},
+		Security: SecurityConfig{
+			TokenTTL:       24 * time.Hour,
+			CookieSameSite: SameSiteLax,
+		},
+		Storage: StorageConfig{
+			Backend: StorageMongo,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, then the YAML file at path (if any),
+// then environment variables, and validates the result.
+// When path is empty the CONFIG_FILE environment variable is used instead.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	if value, ok := os.LookupEnv("SERVER_PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("SERVER_PORT must be a number, got %q", value)
+		}
+		c.Server.Port = port
+	}
+	if value, ok := os.LookupEnv("GRPC_PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("GRPC_PORT must be a number, got %q", value)
+		}
+		c.GRPC.Port = port
+	}
+	if value, ok := os.LookupEnv("CORS_ALLOWED_ORIGINS"); ok {
+		c.Server.AllowedOrigins = splitList(value)
+	}
+	limits := map[string]*int{
+		"GRAPHQL_MAX_DEPTH":      &c.Server.GraphQL.MaxDepth,
+		"GRAPHQL_MAX_COMPLEXITY": &c.Server.GraphQL.MaxComplexity,
+	}
+	for name, target := range limits {
+		if value, ok := os.LookupEnv(name); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s must be a number, got %q", name, value)
+			}
+			*target = n
+		}
+	}
+	if value, ok := os.LookupEnv("RATE_LIMIT_ENABLED"); ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("RATE_LIMIT_ENABLED must be true or false, got %q", value)
+		}
+		c.Server.RateLimit.Enabled = enabled
+	}
+	if value, ok := os.LookupEnv("RATE_LIMIT_STORE"); ok {
+		c.Server.RateLimit.Store = value
+	}
+	durations := map[string]*time.Duration{
+		"SERVER_READ_TIMEOUT":     &c.Server.ReadTimeout,
+		"SERVER_WRITE_TIMEOUT":    &c.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":     &c.Server.IdleTimeout,
+		"SERVER_SHUTDOWN_TIMEOUT": &c.Server.ShutdownTimeout,
+		"MONGO_CONNECT_TIMEOUT":   &c.Mongo.Timeouts.Connect,
+		"MONGO_READ_TIMEOUT":      &c.Mongo.Timeouts.Read,
+		"MONGO_WRITE_TIMEOUT":     &c.Mongo.Timeouts.Write,
+		"MONGO_AGGREGATE_TIMEOUT": &c.Mongo.Timeouts.Aggregate,
+	}
+	for name, target := range durations {
+		if value, ok := os.LookupEnv(name); ok {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("%s must be a duration such as 30s, got %q", name, value)
+			}
+			*target = d
+		}
+	}
+
+	if value, ok := os.LookupEnv("MONGO_URI"); ok {
+		c.Mongo.URI = value
+	}
+	if value, ok := os.LookupEnv("MONGO_DB"); ok {
+		c.Mongo.Database = value
+	}
+	if value, ok := os.LookupEnv("MONGO_DB_USERNAME"); ok {
+		c.Mongo.Username = value
+	}
+	if value, ok := os.LookupEnv("MONGO_DB_PASSWORD"); ok {
+		c.Mongo.Password = value
+	}
+	if value, ok := os.LookupEnv("MONGO_AUTO_MIGRATE"); ok {
+		autoMigrate, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("MONGO_AUTO_MIGRATE must be true or false, got %q", value)
+		}
+		c.Mongo.AutoMigrate = autoMigrate
+	}
+
+	if value, ok := os.LookupEnv("JWT_SECRET"); ok {
+		c.Security.JWTSecret = value
+	}
+	if value, ok := os.LookupEnv("JWT_TOKEN_TTL"); ok {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("JWT_TOKEN_TTL must be a duration such as 24h, got %q", value)
+		}
+		c.Security.TokenTTL = ttl
+	}
+	if value, ok := os.LookupEnv("COOKIE_SAMESITE"); ok {
+		c.Security.CookieSameSite = value
+	}
+
+	if value, ok := os.LookupEnv("STORAGE_BACKEND"); ok {
+		c.Storage.Backend = value
+	}
+
+	return nil
+}
+
+// Validate checks every setting and reports all the problems at once
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	if len(c.Server.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("server.allowedOrigins must list at least one origin"))
+	}
+	for _, origin := range c.Server.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server.readTimeout", c.Server.ReadTimeout},
+		{"server.writeTimeout", c.Server.WriteTimeout},
+		{"server.idleTimeout", c.Server.IdleTimeout},
+		{"server.shutdownTimeout", c.Server.ShutdownTimeout},
+		{"mongo.timeouts.connect", c.Mongo.Timeouts.Connect},
+		{"mongo.timeouts.read", c.Mongo.Timeouts.Read},
+		{"mongo.timeouts.write", c.Mongo.Timeouts.Write},
+		{"mongo.timeouts.aggregate", c.Mongo.Timeouts.Aggregate},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", timeout.name))
+		}
+	}
+
+	if c.Server.GraphQL.MaxDepth < 1 {
+		errs = append(errs, errors.New("server.graphql.maxDepth must be positive"))
+	}
+	if c.Server.GraphQL.MaxComplexity < 1 {
+		errs = append(errs, errors.New("server.graphql.maxComplexity must be positive"))
+	}
+
+	errs = append(errs, c.Server.RateLimit.validate(c.Storage.Backend)...)
+
+	if c.GRPC.Port < 1 || c.GRPC.Port > 65535 {
+		errs = append(errs, fmt.Errorf("grpc.port must be between 1 and 65535, got %d", c.GRPC.Port))
+	} else if c.GRPC.Port == c.Server.Port {
+		errs = append(errs, fmt.Errorf("grpc.port must differ from server.port, both are %d", c.GRPC.Port))
+	}
+
+	switch c.Storage.Backend {
+	case StorageMongo:
+		if !strings.HasPrefix(c.Mongo.URI, "mongodb://") && !strings.HasPrefix(c.Mongo.URI, "mongodb+srv://") {
+			errs = append(errs, fmt.Errorf("mongo.uri must start with mongodb:// or mongodb+srv://, got %q", c.Mongo.URI))
+		}
+		if c.Mongo.Database == "" {
+			errs = append(errs, errors.New("mongo.database must be set"))
+		}
+		if (c.Mongo.Username == "") != (c.Mongo.Password == "") {
+			errs = append(errs, errors.New("mongo.username and mongo.password must be set together"))
+		}
+	case StorageMemory:
+	default:
+		errs = append(errs, fmt.Errorf("storage.backend must be %q or %q, got %q", StorageMongo, StorageMemory, c.Storage.Backend))
+	}
+
+	if len(c.Security.JWTSecret) < minJWTSecretLength {
+		errs = append(errs, fmt.Errorf("security.jwtSecret (JWT_SECRET) must be at least %d characters", minJWTSecretLength))
+	}
+	if c.Security.TokenTTL <= 0 {
+		errs = append(errs, errors.New("security.tokenTTL must be positive"))
+	}
+	switch c.Security.CookieSameSite {
+	case SameSiteLax, SameSiteStrict, SameSiteNone:
+	default:
+		errs = append(errs, fmt.Errorf("security.cookieSameSite must be %q, %q or %q, got %q", SameSiteLax, SameSiteStrict, SameSiteNone, c.Security.CookieSameSite))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// validateOrigin checks origin is exactly a scheme and host, such as https://app.example.org.
+// Credentials are allowed cross-origin, so wildcards would let any site act as the user.
+func validateOrigin(origin string) error {
+	u, err := url.Parse(origin)
+	if err != nil || strings.Contains(origin, "*") || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Host == "" || u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("server.allowedOrigins must be origins such as https://app.example.org without wildcards, got %q", origin)
+	}
+	return nil
+}
+
+// validate checks the rate limits; the mongo store needs the mongo storage backend
+func (r RateLimitConfig) validate(backend string) []error {
+	var errs []error
+
+	switch r.Store {
+	case RateLimitStoreMemory:
+	case RateLimitStoreMongo:
+		if backend != StorageMongo {
+			errs = append(errs, fmt.Errorf("server.rateLimit.store %q needs storage.backend %q", RateLimitStoreMongo, StorageMongo))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("server.rateLimit.store must be %q or %q, got %q", RateLimitStoreMemory, RateLimitStoreMongo, r.Store))
+	}
+
+	limits := []struct {
+		name  string
+		limit RateLimit
+	}{
+		{"server.rateLimit.api", r.API},
+		{"server.rateLimit.auth", r.Auth},
+	}
+	for _, l := range limits {
+		if l.limit.Requests < 1 || l.limit.Per <= 0 || l.limit.Burst < 1 {
+			errs = append(errs, fmt.Errorf("%s requests, per and burst must be positive", l.name))
+		}
+		switch l.limit.Key {
+		case RateLimitByIP, RateLimitByUser, RateLimitByGroup:
+		default:
+			errs = append(errs, fmt.Errorf("%s.key must be %q, %q or %q, got %q", l.name, RateLimitByIP, RateLimitByUser, RateLimitByGroup, l.limit.Key))
+		}
+	}
+
+	login := r.Login
+	if login.Delay <= 0 || login.MaxDelay < login.Delay {
+		errs = append(errs, errors.New("server.rateLimit.login.delay must be positive and no more than maxDelay"))
+	}
+	if login.MaxFailures < 1 || login.Lockout <= 0 {
+		errs = append(errs, errors.New("server.rateLimit.login.maxFailures and lockout must be positive"))
+	}
+
+	return errs
+}
+
+// splitList splits a comma separated environment variable, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
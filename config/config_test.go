@@ -0,0 +1,222 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// envVars are every environment variable Load reads
+var envVars = []string{
+	"CONFIG_FILE", "SERVER_PORT", "GRPC_PORT", "CORS_ALLOWED_ORIGINS",
+	"GRAPHQL_MAX_DEPTH", "GRAPHQL_MAX_COMPLEXITY", "RATE_LIMIT_ENABLED", "RATE_LIMIT_STORE",
+	"SERVER_READ_TIMEOUT", "SERVER_WRITE_TIMEOUT", "SERVER_IDLE_TIMEOUT", "SERVER_SHUTDOWN_TIMEOUT",
+	"MONGO_CONNECT_TIMEOUT", "MONGO_READ_TIMEOUT", "MONGO_WRITE_TIMEOUT", "MONGO_AGGREGATE_TIMEOUT",
+	"MONGO_URI", "MONGO_DB", "MONGO_DB_USERNAME", "MONGO_DB_PASSWORD", "MONGO_AUTO_MIGRATE",
+	"JWT_SECRET", "JWT_TOKEN_TTL", "COOKIE_SAMESITE", "STORAGE_BACKEND",
+}
+
+// setEnv unsets every variable Load reads, then sets env, for the rest of the test
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	for _, name := range envVars {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+}
+
+// validConfig returns the defaults with the one setting they leave out
+func validConfig() Config {
+	cfg := Default()
+	cfg.Security.JWTSecret = testJWTSecret
+	return cfg
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setEnv(t, map[string]string{"JWT_SECRET": testJWTSecret})
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := validConfig(); !reflect.DeepEqual(cfg, want) {
+		t.Errorf("got %+v, want the defaults %+v", cfg, want)
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(Config) bool
+		wantErr string
+	}{
+		{
+			name:  "ports",
+			env:   map[string]string{"SERVER_PORT": "9000", "GRPC_PORT": "9001"},
+			check: func(c Config) bool { return c.Server.Port == 9000 && c.GRPC.Port == 9001 },
+		},
+		{
+			name: "origins",
+			env:  map[string]string{"CORS_ALLOWED_ORIGINS": "https://a.example.org, https://b.example.org,"},
+			check: func(c Config) bool {
+				return reflect.DeepEqual(c.Server.AllowedOrigins, []string{"https://a.example.org", "https://b.example.org"})
+			},
+		},
+		{
+			name: "durations",
+			env:  map[string]string{"SERVER_READ_TIMEOUT": "5s", "MONGO_AGGREGATE_TIMEOUT": "1m", "JWT_TOKEN_TTL": "2h"},
+			check: func(c Config) bool {
+				return c.Server.ReadTimeout == 5*time.Second && c.Mongo.Timeouts.Aggregate == time.Minute && c.Security.TokenTTL == 2*time.Hour
+			},
+		},
+		{
+			name:  "GraphQL limits",
+			env:   map[string]string{"GRAPHQL_MAX_DEPTH": "4", "GRAPHQL_MAX_COMPLEXITY": "250"},
+			check: func(c Config) bool { return c.Server.GraphQL == GraphQLConfig{MaxDepth: 4, MaxComplexity: 250} },
+		},
+		{
+			name:  "memory storage",
+			env:   map[string]string{"STORAGE_BACKEND": "memory", "MONGO_URI": ""},
+			check: func(c Config) bool { return c.Storage.Backend == StorageMemory },
+		},
+		{name: "port not a number", env: map[string]string{"SERVER_PORT": "http"}, wantErr: "SERVER_PORT must be a number"},
+		{name: "port out of range", env: map[string]string{"SERVER_PORT": "70000"}, wantErr: "server.port must be between 1 and 65535"},
+		{name: "gRPC port out of range", env: map[string]string{"GRPC_PORT": "0"}, wantErr: "grpc.port must be between 1 and 65535"},
+		{name: "shared port", env: map[string]string{"GRPC_PORT": "8080"}, wantErr: "grpc.port must differ from server.port"},
+		{name: "duration without a unit", env: map[string]string{"SERVER_READ_TIMEOUT": "30"}, wantErr: "SERVER_READ_TIMEOUT must be a duration"},
+		{name: "negative duration", env: map[string]string{"MONGO_READ_TIMEOUT": "-1s"}, wantErr: "mongo.timeouts.read must be positive"},
+		{name: "token TTL not a duration", env: map[string]string{"JWT_TOKEN_TTL": "a day"}, wantErr: "JWT_TOKEN_TTL must be a duration"},
+		{name: "depth not a number", env: map[string]string{"GRAPHQL_MAX_DEPTH": "deep"}, wantErr: "GRAPHQL_MAX_DEPTH must be a number"},
+		{name: "zero depth", env: map[string]string{"GRAPHQL_MAX_DEPTH": "0"}, wantErr: "server.graphql.maxDepth must be positive"},
+		{name: "negative complexity", env: map[string]string{"GRAPHQL_MAX_COMPLEXITY": "-5"}, wantErr: "server.graphql.maxComplexity must be positive"},
+		{name: "short secret", env: map[string]string{"JWT_SECRET": "secret"}, wantErr: "security.jwtSecret (JWT_SECRET) must be at least 32 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{"JWT_SECRET": testJWTSecret}
+			for name, value := range tt.env {
+				env[name] = value
+			}
+			setEnv(t, env)
+
+			cfg, err := Load("")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(cfg) {
+				t.Errorf("got %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	file := "server:\n  port: 9000\n  readTimeout: 5s\n  graphql:\n    maxDepth: 4\ngrpc:\n  port: 9001\n"
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The environment takes precedence over the file, which takes precedence over the defaults
+	setEnv(t, map[string]string{"CONFIG_FILE": path, "JWT_SECRET": testJWTSecret, "SERVER_PORT": "9100"})
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != 9100 || cfg.Server.ReadTimeout != 5*time.Second || cfg.GRPC.Port != 9001 ||
+		cfg.Server.GraphQL != (GraphQLConfig{MaxDepth: 4, MaxComplexity: 1000}) {
+		t.Errorf("got %+v", cfg)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yml")); err == nil || !strings.Contains(err.Error(), "reading config file") {
+		t.Errorf("got %v for a missing file", err)
+	}
+	if err := os.WriteFile(path, []byte("server:\n  readTimeout: soon\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "parsing config file") {
+		t.Errorf("got %v for an invalid duration in the file", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(*Config)
+		wantErr []string
+	}{
+		{name: "defaults", change: func(c *Config) {}},
+		{name: "highest port", change: func(c *Config) { c.Server.Port = 65535 }},
+		{name: "memory storage without a Mongo URI", change: func(c *Config) { c.Storage.Backend, c.Mongo.URI = StorageMemory, "" }},
+		{
+			name:    "ports",
+			change:  func(c *Config) { c.Server.Port, c.GRPC.Port = 0, 65536 },
+			wantErr: []string{"server.port must be between 1 and 65535, got 0", "grpc.port must be between 1 and 65535, got 65536"},
+		},
+		{
+			name: "durations",
+			change: func(c *Config) {
+				c.Server.WriteTimeout, c.Mongo.Timeouts.Connect, c.Security.TokenTTL = 0, -time.Second, 0
+			},
+			wantErr: []string{"server.writeTimeout must be positive", "mongo.timeouts.connect must be positive", "security.tokenTTL must be positive"},
+		},
+		{
+			name:    "GraphQL limits",
+			change:  func(c *Config) { c.Server.GraphQL = GraphQLConfig{} },
+			wantErr: []string{"server.graphql.maxDepth must be positive", "server.graphql.maxComplexity must be positive"},
+		},
+		{
+			name:    "wildcard origin",
+			change:  func(c *Config) { c.Server.AllowedOrigins = []string{"https://*.example.org"} },
+			wantErr: []string{"server.allowedOrigins must be origins"},
+		},
+		{
+			name:    "unknown storage backend",
+			change:  func(c *Config) { c.Storage.Backend = "postgres" },
+			wantErr: []string{`storage.backend must be "mongo" or "memory", got "postgres"`},
+		},
+		{
+			name:    "mongo rate limit store without mongo",
+			change:  func(c *Config) { c.Storage.Backend, c.Server.RateLimit.Store = StorageMemory, RateLimitStoreMongo },
+			wantErr: []string{`server.rateLimit.store "mongo" needs storage.backend "mongo"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.change(&cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("got %v", err)
+				}
+				return
+			}
+			// Every problem is reported at once
+			for _, want := range tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("got error %v, want it to include %q", err, want)
+				}
+			}
+		})
+	}
+}
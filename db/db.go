@@ -2,38 +2,45 @@ package db
 
 import (
 	"context"
-	"log"
-	"os"
+	"log/slog"
 
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/metrics"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var collection *mongo.Collection
 
-func ConnectToMongo() (*mongo.Client, error) {
+// ConnectToMongo connects to the MongoDB server described by cfg
+func ConnectToMongo(cfg config.MongoConfig) (*mongo.Client, error) {
 	// MongoDb connection string
-	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	clientOptions := options.Client().ApplyURI(cfg.URI)
 
-	// getting username and password from .env
-	username := os.Getenv("MONGO_DB_USERNAME")
-	password := os.Getenv("MONGO_DB_PASSWORD")
+	// record command timings for /metrics
+	clientOptions.SetMonitor(metrics.MongoMonitor())
+
+	// fail fast when no server can be reached, rather than waiting on the driver's 30s default
+	clientOptions.SetConnectTimeout(cfg.Timeouts.Connect)
+	clientOptions.SetServerSelectionTimeout(cfg.Timeouts.Connect)
 
 	// setting auth credentials
-	clientOptions.SetAuth(options.Credential{
-		Username: username,
-		Password: password,
-	})
+	if cfg.Username != "" {
+		clientOptions.SetAuth(options.Credential{
+			Username: cfg.Username,
+			Password: cfg.Password,
+		})
+	}
 
 	// Connect to mongo
-	// TODO: check either using TODO or Background
-	client, err := mongo.Connect(context.Background(), clientOptions)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Connect)
+	defer cancel()
+	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
 
-	log.Println("Connected to mongo...")
+	slog.Info("Connected to mongo")
 
 	return client, nil
 }
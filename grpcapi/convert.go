@@ -0,0 +1,138 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"time"
+
+	volunteerv1 "github.com/volunteerService-backend/proto/volunteer/v1"
+	"github.com/volunteerService-backend/services"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timestamp converts t, leaving it unset when t is zero
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// fromTimestamp converts ts, returning the zero time when it's unset
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toVolunteer(v services.Volunteer) *volunteerv1.Volunteer {
+	return &volunteerv1.Volunteer{
+		VolunteerId:   v.VolunteerID,
+		VolunteerName: v.VolunteerName,
+		JoinedAt:      timestamp(v.JoinedAt),
+	}
+}
+
+func fromVolunteer(v *volunteerv1.Volunteer) services.Volunteer {
+	return services.Volunteer{
+		VolunteerID:   v.GetVolunteerId(),
+		VolunteerName: v.GetVolunteerName(),
+		JoinedAt:      fromTimestamp(v.GetJoinedAt()),
+	}
+}
+
+func toTodo(t services.Todo) *volunteerv1.Todo {
+	todo := &volunteerv1.Todo{
+		Id:               t.ID,
+		Task:             t.Task,
+		Description:      t.Description,
+		OrgId:            t.OrganisationID,
+		OrgName:          t.OrganisationName,
+		VolType:          t.VolunteerType,
+		OrgType:          t.OrganisationType,
+		Completed:        t.Completed,
+		Time:             timestamp(t.Time),
+		EndTime:          timestamp(t.EndTime),
+		CreatedAt:        timestamp(t.CreatedAt),
+		VolunteersNeeded: int32(t.VolunteersNeeded),
+	}
+	for _, v := range t.Volunteer {
+		todo.Volunteers = append(todo.Volunteers, toVolunteer(v))
+	}
+	return todo
+}
+
+func toTodos(todos []services.Todo) []*volunteerv1.Todo {
+	converted := make([]*volunteerv1.Todo, 0, len(todos))
+	for _, t := range todos {
+		converted = append(converted, toTodo(t))
+	}
+	return converted
+}
+
+// fromTodo converts a todo to create, ignoring the fields the server sets itself
+func fromTodo(t *volunteerv1.Todo) services.Todo {
+	return services.Todo{
+		Task:             t.GetTask(),
+		Description:      t.GetDescription(),
+		OrganisationID:   t.GetOrgId(),
+		OrganisationName: t.GetOrgName(),
+		VolunteerType:    t.GetVolType(),
+		OrganisationType: t.GetOrgType(),
+		Completed:        t.GetCompleted(),
+		Time:             fromTimestamp(t.GetTime()),
+		EndTime:          fromTimestamp(t.GetEndTime()),
+		VolunteersNeeded: int(t.GetVolunteersNeeded()),
+	}
+}
+
+// toUser converts a user's profile, leaving out the password
+func toUser(u services.User) *volunteerv1.User {
+	return &volunteerv1.User{
+		Id:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		ContactNo: u.ContactNumber,
+		UserType:  u.UserType,
+		VolType:   u.VolunteerType,
+		OrgName:   u.OrganisationName,
+		OrgType:   u.OrganisationType,
+	}
+}
+
+var todoActions = map[string]volunteerv1.TodoAction{
+	services.HistoryCreate: volunteerv1.TodoAction_TODO_ACTION_CREATE,
+	services.HistoryUpdate: volunteerv1.TodoAction_TODO_ACTION_UPDATE,
+	services.HistoryDelete: volunteerv1.TodoAction_TODO_ACTION_DELETE,
+	services.HistoryRoster: volunteerv1.TodoAction_TODO_ACTION_ROSTER,
+}
+
+func toEvent(entry services.HistoryEntry) *volunteerv1.TodoEvent {
+	event := &volunteerv1.TodoEvent{
+		TodoId:    entry.TodoID,
+		Action:    todoActions[entry.Action],
+		Actor:     entry.Actor,
+		Timestamp: timestamp(entry.Timestamp),
+	}
+	for _, change := range entry.Changes {
+		event.Changes = append(event.Changes, &volunteerv1.FieldChange{
+			Field:   change.Field,
+			OldJson: jsonValue(change.Old),
+			NewJson: jsonValue(change.New),
+		})
+	}
+	return event
+}
+
+// jsonValue encodes value as JSON, or returns "" when it's unset
+func jsonValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,62 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/services"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps a domain error to the gRPC status clients see, the counterpart of the
+// REST handlers' writeError. Unexpected errors are logged and hidden behind Internal.
+func toStatus(ctx context.Context, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, services.ErrConflict):
+		code = codes.AlreadyExists
+	case errors.Is(err, services.ErrValidation):
+		code = codes.InvalidArgument
+	case errors.Is(err, services.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, services.ErrUnauthorized):
+		code = codes.Unauthenticated
+	case errors.Is(err, services.ErrUnsupported):
+		code = codes.Unimplemented
+	case services.IsCanceled(err):
+		return status.Error(codes.Canceled, "Request cancelled")
+	case services.IsTimeout(err):
+		logging.FromContext(ctx).Warn("Database timeout", "error", err)
+		return status.Error(codes.DeadlineExceeded, "The database took too long to respond")
+	}
+
+	if code == codes.Internal {
+		logging.FromContext(ctx).Error("Internal error", "error", err)
+		return status.Error(codes.Internal, "An unexpected error occurred")
+	}
+
+	var domainErr *services.Error
+	if !errors.As(err, &domainErr) {
+		return status.Error(code, err.Error())
+	}
+
+	st := status.New(code, domainErr.Msg)
+	if len(domainErr.Fields) > 0 {
+		details := &errdetails.BadRequest{}
+		for _, f := range domainErr.Fields {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Field,
+				Description: f.Message,
+			})
+		}
+		if withDetails, err := st.WithDetails(details); err == nil {
+			st = withDetails
+		}
+	}
+	return st.Err()
+}
@@ -0,0 +1,108 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey is the metadata key carrying a caller's request ID, as X-Request-ID does over HTTP
+const requestIDKey = "x-request-id"
+
+type contextKey struct{}
+
+// actorFrom returns the email of the authenticated caller, recorded as the actor of changes
+func actorFrom(ctx context.Context) string {
+	email, _ := ctx.Value(contextKey{}).(string)
+	return email
+}
+
+// authenticate checks the JWT in the "authorization" metadata, the same token the REST API
+// issues at login, and returns ctx carrying the caller's email and a request-scoped logger.
+// Tokens of deactivated users are rejected.
+func (s *Server) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := first(md, requestIDKey)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = logging.NewRequestID()
+	}
+	ctx = logging.WithLogger(ctx, slog.Default().With("requestId", requestID))
+
+	header := first(md, "authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ctx, status.Error(codes.Unauthenticated, "Authentication required")
+	}
+	email, err := s.models.Authenticate(ctx, strings.TrimPrefix(header, "Bearer "))
+	if errors.Is(err, services.ErrUnauthorized) {
+		return ctx, status.Error(codes.Unauthenticated, "Invalid token")
+	}
+	if err != nil {
+		return ctx, toStatus(ctx, err)
+	}
+
+	return context.WithValue(ctx, contextKey{}, email), nil
+}
+
+// first returns the first value of key in md, or ""
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// logCall logs a finished call with its outcome
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	logging.FromContext(ctx).Info("RPC",
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration", time.Since(start).String(),
+	)
+}
+
+// unaryInterceptor authenticates and logs every unary call
+func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	var res interface{}
+	ctx, err := s.authenticate(ctx)
+	if err == nil {
+		res, err = handler(ctx, req)
+	}
+
+	logCall(ctx, info.FullMethod, start, err)
+	return res, err
+}
+
+// authenticatedStream is a server stream whose context carries the authenticated caller
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// streamInterceptor authenticates and logs every streaming call
+func (s *Server) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	ctx, err := s.authenticate(ss.Context())
+	if err == nil {
+		err = handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+
+	logCall(ctx, info.FullMethod, start, err)
+	return err
+}
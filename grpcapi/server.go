@@ -0,0 +1,233 @@
+// Package grpcapi serves the todos and users over gRPC for internal Go services,
+// on top of the same service layer as the REST API.
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+
+	volunteerv1 "github.com/volunteerService-backend/proto/volunteer/v1"
+	"github.com/volunteerService-backend/services"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TodoService is the todo behaviour the gRPC API depends on, implemented by *services.Models
+type TodoService interface {
+	GetAllTodos(ctx context.Context) ([]services.Todo, error)
+	GetTodoById(ctx context.Context, id string) (services.Todo, error)
+	GetTodosByOrg(ctx context.Context, orgName string) ([]services.Todo, error)
+	GetTodosByVolType(ctx context.Context, volType string) ([]services.Todo, error)
+	InsertTodo(ctx context.Context, entry services.Todo, actor string) (string, error)
+	UpdateTodo(ctx context.Context, id string, entry services.Todo, actor string) error
+	DeleteTodo(ctx context.Context, id string, actor string) error
+	JoinTodo(ctx context.Context, id string, volunteer services.Volunteer, actor string) error
+	LeaveTodo(ctx context.Context, id, volunteerID, actor string) error
+}
+
+// Server implements the VolunteerService
+type Server struct {
+	volunteerv1.UnimplementedVolunteerServiceServer
+	models *services.Models
+	todos  TodoService
+}
+
+// New returns a gRPC server with the VolunteerService registered, authenticating every call
+func New(models *services.Models) *grpc.Server {
+	server := &Server{models: models, todos: models}
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(server.unaryInterceptor),
+		grpc.StreamInterceptor(server.streamInterceptor),
+	)
+	volunteerv1.RegisterVolunteerServiceServer(s, server)
+	return s
+}
+
+// getTodo returns the todo with its names resolved, as a response
+func (s *Server) getTodo(ctx context.Context, id string) (*volunteerv1.Todo, error) {
+	todo, err := s.todos.GetTodoById(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toTodo(todo), nil
+}
+
+func (s *Server) ListTodos(ctx context.Context, req *volunteerv1.ListTodosRequest) (*volunteerv1.ListTodosResponse, error) {
+	var todos []services.Todo
+	var err error
+	switch {
+	case req.GetOrgName() != "":
+		todos, err = s.todos.GetTodosByOrg(ctx, req.GetOrgName())
+	case req.GetVolType() != "":
+		todos, err = s.todos.GetTodosByVolType(ctx, req.GetVolType())
+	default:
+		todos, err = s.todos.GetAllTodos(ctx)
+	}
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	if userID := req.GetAvailableFor(); userID != "" {
+		todos, err = s.models.FilterByAvailability(ctx, todos, userID)
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+	}
+
+	return &volunteerv1.ListTodosResponse{Todos: toTodos(todos)}, nil
+}
+
+func (s *Server) GetTodo(ctx context.Context, req *volunteerv1.GetTodoRequest) (*volunteerv1.Todo, error) {
+	return s.getTodo(ctx, req.GetId())
+}
+
+func (s *Server) CreateTodo(ctx context.Context, req *volunteerv1.CreateTodoRequest) (*volunteerv1.Todo, error) {
+	if req.GetTodo() == nil {
+		return nil, status.Error(codes.InvalidArgument, "todo is required")
+	}
+
+	id, err := s.todos.InsertTodo(ctx, fromTodo(req.GetTodo()), actorFrom(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return s.getTodo(ctx, id)
+}
+
+func (s *Server) UpdateTodo(ctx context.Context, req *volunteerv1.UpdateTodoRequest) (*volunteerv1.Todo, error) {
+	existing, err := s.todos.GetTodoById(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	entry := services.Todo{Task: existing.Task, Completed: existing.Completed}
+	if req.Task != nil {
+		entry.Task = req.GetTask()
+	}
+	if req.Completed != nil {
+		entry.Completed = req.GetCompleted()
+	}
+
+	err = s.todos.UpdateTodo(ctx, req.GetId(), entry, actorFrom(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return s.getTodo(ctx, req.GetId())
+}
+
+func (s *Server) DeleteTodo(ctx context.Context, req *volunteerv1.DeleteTodoRequest) (*volunteerv1.DeleteTodoResponse, error) {
+	err := s.todos.DeleteTodo(ctx, req.GetId(), actorFrom(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return &volunteerv1.DeleteTodoResponse{}, nil
+}
+
+// JoinTodo signs the volunteer up, failing with FAILED_PRECONDITION on a schedule conflict
+// unless the caller allows conflicts, in which case they're returned as warnings
+func (s *Server) JoinTodo(ctx context.Context, req *volunteerv1.JoinTodoRequest) (*volunteerv1.JoinTodoResponse, error) {
+	if req.GetVolunteer().GetVolunteerId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "volunteer.volunteer_id is required")
+	}
+
+	todo, err := s.todos.GetTodoById(ctx, req.GetTodoId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	warnings, err := s.models.CheckSchedule(ctx, todo, req.GetVolunteer().GetVolunteerId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if len(warnings) > 0 && !req.GetAllowConflicts() {
+		return nil, scheduleConflict(warnings)
+	}
+
+	err = s.todos.JoinTodo(ctx, req.GetTodoId(), fromVolunteer(req.GetVolunteer()), actorFrom(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	updated, err := s.getTodo(ctx, req.GetTodoId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &volunteerv1.JoinTodoResponse{Todo: updated, Warnings: warnings}, nil
+}
+
+// scheduleConflict reports each clash as a precondition violation
+func scheduleConflict(warnings []string) error {
+	st := status.New(codes.FailedPrecondition, "Volunteer schedule conflict")
+
+	details := &errdetails.PreconditionFailure{}
+	for _, warning := range warnings {
+		details.Violations = append(details.Violations, &errdetails.PreconditionFailure_Violation{
+			Type:        "SCHEDULE",
+			Description: warning,
+		})
+	}
+	if withDetails, err := st.WithDetails(details); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+func (s *Server) LeaveTodo(ctx context.Context, req *volunteerv1.LeaveTodoRequest) (*volunteerv1.Todo, error) {
+	err := s.todos.LeaveTodo(ctx, req.GetTodoId(), req.GetVolunteerId(), actorFrom(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return s.getTodo(ctx, req.GetTodoId())
+}
+
+func (s *Server) GetUsers(ctx context.Context, req *volunteerv1.GetUsersRequest) (*volunteerv1.GetUsersResponse, error) {
+	if len(req.GetIds()) == 0 {
+		return &volunteerv1.GetUsersResponse{}, nil
+	}
+
+	users, err := s.models.GetUsersByID(ctx, req.GetIds())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	res := &volunteerv1.GetUsersResponse{}
+	for _, user := range users {
+		res.Users = append(res.Users, toUser(user))
+	}
+	return res, nil
+}
+
+// WatchTodos streams todo changes as they're recorded until the client goes away.
+// A client that can't keep up misses changes rather than slowing down writes.
+// Headers are sent once subscribed, so clients waiting on them won't miss a change made after.
+func (s *Server) WatchTodos(req *volunteerv1.WatchTodosRequest, stream volunteerv1.VolunteerService_WatchTodosServer) error {
+	changes, unsubscribe := s.models.SubscribeTodoChanges()
+	defer unsubscribe()
+
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return fmt.Errorf("sending headers: %w", err)
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-changes:
+			if req.GetTodoId() != "" && entry.TodoID != req.GetTodoId() {
+				continue
+			}
+			if err := stream.Send(toEvent(entry)); err != nil {
+				return fmt.Errorf("sending todo event: %w", err)
+			}
+		}
+	}
+}
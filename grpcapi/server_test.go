@@ -0,0 +1,340 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+	volunteerv1 "github.com/volunteerService-backend/proto/volunteer/v1"
+	"github.com/volunteerService-backend/services"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testJWTSecret = "test-secret-that-is-at-least-32-characters"
+	testPassword  = "Sup3r$ecret"
+)
+
+// newTestClient serves the API over a fresh in-memory store and returns a client connected to it,
+// along with the services behind it
+func newTestClient(t *testing.T) (volunteerv1.VolunteerServiceClient, *services.Models) {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Storage.Backend = config.StorageMemory
+	cfg.Security.JWTSecret = testJWTSecret
+	models := services.New(nil, cfg)
+
+	listener := bufconn.Listen(1 << 20)
+	server := New(models)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return volunteerv1.NewVolunteerServiceClient(conn), models
+}
+
+// signup registers user with models and returns their ID and a context carrying their token
+func signup(t *testing.T, models *services.Models, user services.User) (string, context.Context) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := models.Signup(ctx, user); err != nil {
+		t.Fatalf("signing up: %v", err)
+	}
+	token, err := models.Login(ctx, httptest.NewRecorder(), user.Email, user.Password)
+	if err != nil {
+		t.Fatalf("logging in: %v", err)
+	}
+	stored, err := models.GetUserByEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return stored.ID, metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func organisationFixture() services.User {
+	return services.User{
+		Email:            "org@example.com",
+		Password:         testPassword,
+		ContactNumber:    "+447911000001",
+		UserType:         "organisation",
+		OrganisationName: "Helping Hands",
+		OrganisationType: "charity",
+	}
+}
+
+func volunteerFixture() services.User {
+	return services.User{
+		FirstName:     "Ada",
+		LastName:      "Lovelace",
+		Email:         "ada@example.com",
+		Password:      testPassword,
+		ContactNumber: "+447911000002",
+		UserType:      "volunteer",
+		VolunteerType: "general",
+	}
+}
+
+func todoFixture() *volunteerv1.Todo {
+	return &volunteerv1.Todo{
+		Task:             "Sort donations",
+		OrgName:          "Helping Hands",
+		VolType:          "general",
+		OrgType:          "charity",
+		VolunteersNeeded: 3,
+	}
+}
+
+// expectCode fails the test unless err has the given status code
+func expectCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("got code %v, want %v: %v", got, want, err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"no token", context.Background()},
+		{"not a bearer token", metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic abc")},
+		{"invalid token", metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer abc")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ListTodos(tt.ctx, &volunteerv1.ListTodosRequest{})
+			expectCode(t, err, codes.Unauthenticated)
+
+			stream, err := client.WatchTodos(tt.ctx, &volunteerv1.WatchTodosRequest{})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			expectCode(t, err, codes.Unauthenticated)
+		})
+	}
+}
+
+func TestDeactivatedToken(t *testing.T) {
+	client, models := newTestClient(t)
+	_, ctx := signup(t, models, volunteerFixture())
+
+	if _, err := client.ListTodos(ctx, &volunteerv1.ListTodosRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := models.SetDeactivated(context.Background(), volunteerFixture().Email, true); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.ListTodos(ctx, &volunteerv1.ListTodosRequest{})
+	expectCode(t, err, codes.Unauthenticated)
+
+	models.SetDeactivated(context.Background(), volunteerFixture().Email, false)
+	if _, err := client.ListTodos(ctx, &volunteerv1.ListTodosRequest{}); err != nil {
+		t.Errorf("got %v once reactivated", err)
+	}
+}
+
+func TestTodoCRUD(t *testing.T) {
+	client, models := newTestClient(t)
+	orgID, ctx := signup(t, models, organisationFixture())
+
+	created, err := client.CreateTodo(ctx, &volunteerv1.CreateTodoRequest{Todo: todoFixture()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.GetId() == "" || created.GetOrgId() != orgID || created.GetCreatedAt() == nil {
+		t.Errorf("got created todo %+v", created)
+	}
+
+	_, err = client.CreateTodo(ctx, &volunteerv1.CreateTodoRequest{Todo: &volunteerv1.Todo{VolType: "astronaut"}})
+	expectCode(t, err, codes.InvalidArgument)
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = badRequest.GetFieldViolations()
+		}
+	}
+	if len(violations) == 0 {
+		t.Errorf("got no field violations: %v", err)
+	}
+
+	for _, req := range []*volunteerv1.ListTodosRequest{{}, {OrgName: "Helping Hands"}, {VolType: "general"}} {
+		list, err := client.ListTodos(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list.GetTodos()) != 1 {
+			t.Errorf("listing %+v got %d todos, want 1", req, len(list.GetTodos()))
+		}
+	}
+	if list, _ := client.ListTodos(ctx, &volunteerv1.ListTodosRequest{VolType: "medical"}); len(list.GetTodos()) != 0 {
+		t.Errorf("got %d medical todos, want 0", len(list.GetTodos()))
+	}
+
+	completed := true
+	updated, err := client.UpdateTodo(ctx, &volunteerv1.UpdateTodoRequest{Id: created.GetId(), Completed: &completed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.GetCompleted() || updated.GetTask() != "Sort donations" {
+		t.Errorf("got updated todo %+v, want completed with the task unchanged", updated)
+	}
+
+	if _, err := client.DeleteTodo(ctx, &volunteerv1.DeleteTodoRequest{Id: created.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetTodo(ctx, &volunteerv1.GetTodoRequest{Id: created.GetId()})
+	expectCode(t, err, codes.NotFound)
+}
+
+func TestJoinAndLeave(t *testing.T) {
+	client, models := newTestClient(t)
+	_, ctx := signup(t, models, organisationFixture())
+	adaID, _ := signup(t, models, volunteerFixture())
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	first := todoFixture()
+	first.Time = timestamp(start)
+	todo, err := client.CreateTodo(ctx, &volunteerv1.CreateTodoRequest{Todo: first})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	joined, err := client.JoinTodo(ctx, &volunteerv1.JoinTodoRequest{
+		TodoId:    todo.GetId(),
+		Volunteer: &volunteerv1.Volunteer{VolunteerId: adaID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	volunteers := joined.GetTodo().GetVolunteers()
+	if len(volunteers) != 1 || volunteers[0].GetVolunteerName() != "Ada Lovelace" || volunteers[0].GetJoinedAt() == nil {
+		t.Fatalf("got volunteers %+v", volunteers)
+	}
+
+	// Joining twice, or without saying who, is rejected
+	_, err = client.JoinTodo(ctx, &volunteerv1.JoinTodoRequest{TodoId: todo.GetId(), Volunteer: &volunteerv1.Volunteer{VolunteerId: adaID}})
+	expectCode(t, err, codes.AlreadyExists)
+	_, err = client.JoinTodo(ctx, &volunteerv1.JoinTodoRequest{TodoId: todo.GetId(), Volunteer: &volunteerv1.Volunteer{}})
+	expectCode(t, err, codes.InvalidArgument)
+
+	// A second todo at the same time clashes
+	clash, err := client.CreateTodo(ctx, &volunteerv1.CreateTodoRequest{Todo: first})
+	if err != nil {
+		t.Fatal(err)
+	}
+	join := &volunteerv1.JoinTodoRequest{TodoId: clash.GetId(), Volunteer: &volunteerv1.Volunteer{VolunteerId: adaID}}
+	_, err = client.JoinTodo(ctx, join)
+	expectCode(t, err, codes.FailedPrecondition)
+
+	join.AllowConflicts = true
+	forced, err := client.JoinTodo(ctx, join)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forced.GetWarnings()) == 0 {
+		t.Error("got no warnings for a conflicting join")
+	}
+
+	left, err := client.LeaveTodo(ctx, &volunteerv1.LeaveTodoRequest{TodoId: todo.GetId(), VolunteerId: adaID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left.GetVolunteers()) != 0 {
+		t.Errorf("got volunteers %+v after leaving", left.GetVolunteers())
+	}
+
+	_, err = client.LeaveTodo(ctx, &volunteerv1.LeaveTodoRequest{TodoId: todo.GetId(), VolunteerId: adaID})
+	expectCode(t, err, codes.NotFound)
+}
+
+func TestGetUsers(t *testing.T) {
+	client, models := newTestClient(t)
+	orgID, ctx := signup(t, models, organisationFixture())
+	adaID, _ := signup(t, models, volunteerFixture())
+
+	res, err := client.GetUsers(ctx, &volunteerv1.GetUsersRequest{Ids: []string{orgID, adaID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	emails := map[string]string{}
+	for _, user := range res.GetUsers() {
+		emails[user.GetId()] = user.GetEmail()
+	}
+	if emails[orgID] != "org@example.com" || emails[adaID] != "ada@example.com" {
+		t.Errorf("got users %+v", res.GetUsers())
+	}
+
+	_, err = client.GetUsers(ctx, &volunteerv1.GetUsersRequest{Ids: []string{"not-an-id"}})
+	expectCode(t, err, codes.InvalidArgument)
+}
+
+func TestWatchTodos(t *testing.T) {
+	client, models := newTestClient(t)
+	_, ctx := signup(t, models, organisationFixture())
+
+	other, err := client.CreateTodo(ctx, &volunteerv1.CreateTodoRequest{Todo: todoFixture()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	watched, err := client.CreateTodo(ctx, &volunteerv1.CreateTodoRequest{Todo: todoFixture()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watchCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchTodos(watchCtx, &volunteerv1.WatchTodosRequest{TodoId: watched.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Headers arrive once the server is subscribed
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	completed := true
+	if _, err := client.UpdateTodo(ctx, &volunteerv1.UpdateTodoRequest{Id: other.GetId(), Completed: &completed}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UpdateTodo(ctx, &volunteerv1.UpdateTodoRequest{Id: watched.GetId(), Completed: &completed}); err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.GetTodoId() != watched.GetId() || event.GetAction() != volunteerv1.TodoAction_TODO_ACTION_UPDATE || event.GetActor() != "org@example.com" {
+		t.Errorf("got event %+v", event)
+	}
+	if changes := event.GetChanges(); len(changes) != 1 || changes[0].GetField() != "completed" || changes[0].GetNewJson() != "true" {
+		t.Errorf("got changes %+v", changes)
+	}
+}
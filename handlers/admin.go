@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/services"
+)
+
+// requireAdmin rejects requests from users who are not active administrators.
+// It must come after requireAuth.
+func (h *Handlers) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := h.models.GetUserByEmail(r.Context(), currentUserEmail(r))
+		if err != nil && !errors.Is(err, services.ErrNotFound) {
+			writeError(w, r, err)
+			return
+		}
+		if err != nil || !user.IsAdmin() || user.DeactivatedAt != nil {
+			writeProblem(w, r, http.StatusForbidden, "Administrator access required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// exportBackup streams a backup archive of the whole database
+func (h *Handlers) exportBackup(w http.ResponseWriter, r *http.Request) {
+	// A backup can take far longer than the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		logging.FromContext(r.Context()).Warn("Error lifting the write deadline", "error", err)
+	}
+
+	filename := "volunteer-backup-" + time.Now().UTC().Format("20060102T150405Z") + ".zip"
+	out := &download{w: w, contentType: "application/zip", filename: filename}
+	manifest, err := h.models.ExportBackup(r.Context(), out)
+	if err != nil && !out.started {
+		writeError(w, r, err)
+		return
+	}
+	if err != nil {
+		// The status is already sent, so the client can only tell from the broken download
+		logging.FromContext(r.Context()).Error("Error streaming backup", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	logging.FromContext(r.Context()).Info("Exported backup",
+		"user", currentUserEmail(r), "schemaVersion", manifest.SchemaVersion, "collections", len(manifest.Collections))
+}
+
+// download sends the headers of a file attachment just before its first byte, so errors
+// found earlier can still be reported as problems
+type download struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (d *download) Write(p []byte) (int, error) {
+	if !d.started {
+		d.started = true
+		d.w.Header().Set("Content-Type", d.contentType)
+		d.w.Header().Set("Content-Disposition", `attachment; filename="`+d.filename+`"`)
+		d.w.WriteHeader(http.StatusOK)
+	}
+	return d.w.Write(p)
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/services"
+)
+
+// parseAnalyticsRange reads the 'orgName', 'from' and 'to' query parameters.
+// Dates may be given as RFC3339 timestamps or as plain YYYY-MM-DD days.
+func parseAnalyticsRange(r *http.Request) (services.AnalyticsRange, error) {
+	query := r.URL.Query()
+
+	orgName := query.Get("orgName")
+	if orgName == "" {
+		return services.AnalyticsRange{}, fmt.Errorf("missing 'orgName' query parameter")
+	}
+
+	from, err := parseAnalyticsDate(query.Get("from"))
+	if err != nil {
+		return services.AnalyticsRange{}, fmt.Errorf("invalid 'from' query parameter")
+	}
+	to, err := parseAnalyticsDate(query.Get("to"))
+	if err != nil {
+		return services.AnalyticsRange{}, fmt.Errorf("invalid 'to' query parameter")
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return services.AnalyticsRange{}, fmt.Errorf("'from' must be before 'to'")
+	}
+
+	return services.AnalyticsRange{OrganisationName: orgName, From: from, To: to}, nil
+}
+
+// authorizedAnalyticsRange parses the analytics range, checks the caller belongs to its
+// organisation and scopes it to the caller's organisation ID. Otherwise it writes the
+// problem and returns false.
+func (h *Handlers) authorizedAnalyticsRange(w http.ResponseWriter, r *http.Request) (services.AnalyticsRange, bool) {
+	analyticsRange, err := parseAnalyticsRange(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return services.AnalyticsRange{}, false
+	}
+
+	organisation, err := h.models.AuthorizeOrganisation(r.Context(), currentUserEmail(r), analyticsRange.OrganisationName)
+	if err != nil {
+		writeError(w, r, err)
+		return services.AnalyticsRange{}, false
+	}
+	analyticsRange.OrganisationID = organisation.ID
+
+	return analyticsRange, true
+}
+
+func parseAnalyticsDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// writeAnalytics sends the result as JSON, or as a CSV download when 'format=csv' is requested
+func writeAnalytics(w http.ResponseWriter, r *http.Request, name string, result interface{}, header []string, rows [][]string) {
+	if r.URL.Query().Get("format") != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write(header)
+	writer.WriteAll(rows)
+	if err := writer.Error(); err != nil {
+		logging.FromContext(r.Context()).Error("Error writing CSV response", "error", err)
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
+
+func (h *Handlers) getOrgStatusAnalytics(w http.ResponseWriter, r *http.Request) {
+	analyticsRange, ok := h.authorizedAnalyticsRange(w, r)
+	if !ok {
+		return
+	}
+
+	buckets, err := h.models.GetStatusOverTime(r.Context(), analyticsRange, r.URL.Query().Get("bucket"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var rows [][]string
+	for _, b := range buckets {
+		rows = append(rows, []string{
+			b.Bucket.Format(time.RFC3339),
+			strconv.Itoa(b.Open),
+			strconv.Itoa(b.Completed),
+			strconv.Itoa(b.Total),
+		})
+	}
+
+	writeAnalytics(w, r, "status", buckets, []string{"bucket", "open", "completed", "total"}, rows)
+}
+
+func (h *Handlers) getOrgFillAnalytics(w http.ResponseWriter, r *http.Request) {
+	analyticsRange, ok := h.authorizedAnalyticsRange(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.models.GetFillStats(r.Context(), analyticsRange)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	rows := [][]string{{
+		strconv.Itoa(stats.Tasks),
+		strconv.Itoa(stats.FilledTasks),
+		formatFloat(stats.AverageFillRate),
+		formatFloat(stats.AverageTimeToFill),
+	}}
+
+	writeAnalytics(w, r, "fill", stats, []string{"tasks", "filledTasks", "avgFillRate", "avgTimeToFillHours"}, rows)
+}
+
+func (h *Handlers) getOrgVolTypeAnalytics(w http.ResponseWriter, r *http.Request) {
+	analyticsRange, ok := h.authorizedAnalyticsRange(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := h.models.GetVolunteerCountsByType(r.Context(), analyticsRange)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var rows [][]string
+	for _, c := range counts {
+		rows = append(rows, []string{c.VolunteerType, strconv.Itoa(c.Volunteers), strconv.Itoa(c.Joins)})
+	}
+
+	writeAnalytics(w, r, "voltypes", counts, []string{"volType", "volunteers", "joins"}, rows)
+}
+
+func (h *Handlers) getOrgRepeatAnalytics(w http.ResponseWriter, r *http.Request) {
+	analyticsRange, ok := h.authorizedAnalyticsRange(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.models.GetRepeatVolunteerStats(r.Context(), analyticsRange)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	rows := [][]string{{
+		strconv.Itoa(stats.Volunteers),
+		strconv.Itoa(stats.RepeatVolunteers),
+		formatFloat(stats.RepeatVolunteerRate),
+	}}
+
+	writeAnalytics(w, r, "repeat", stats, []string{"volunteers", "repeatVolunteers", "repeatVolunteerRate"}, rows)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/volunteerService-backend/services"
+)
+
+func TestOrgAnalytics(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	_, volunteerCookie := s.signupAndLogin(volunteerFixture())
+
+	tests := []struct {
+		name       string
+		path       string
+		cookie     *http.Cookie
+		wantStatus int
+	}{
+		{"missing orgName", "/api/v1/analytics/org/fill", orgCookie, http.StatusBadRequest},
+		{"bad date", "/api/v1/analytics/org/fill?orgName=Helping+Hands&from=yesterday", orgCookie, http.StatusBadRequest},
+		{"anonymous", "/api/v1/analytics/org/fill?orgName=Helping+Hands", nil, http.StatusUnauthorized},
+		{"volunteer", "/api/v1/analytics/org/fill?orgName=Helping+Hands", volunteerCookie, http.StatusForbidden},
+		{"other organisation", "/api/v1/analytics/org/fill?orgName=Food+Bank", orgCookie, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request{method: http.MethodGet, path: tt.path}
+			if tt.cookie != nil {
+				req.cookies = []*http.Cookie{tt.cookie}
+			}
+			expectProblem(t, s.do(req), tt.wantStatus)
+		})
+	}
+
+	bucket := request{method: http.MethodGet, path: "/api/v1/analytics/org/status?orgName=Helping+Hands&bucket=year", cookies: []*http.Cookie{orgCookie}}
+	if p := expectProblem(t, s.do(bucket), http.StatusBadRequest); !hasFieldError(p, "bucket") {
+		t.Errorf("got %+v, want a bucket field error", p)
+	}
+}
+
+// The memory backend works the reports out without an aggregation pipeline
+func TestOrgAnalyticsResults(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	adaID, _ := s.signupAndLogin(volunteerFixture())
+
+	// 2030-01-07 is a Monday, so both todos fall in the week starting Sunday 2030-01-06
+	monday := time.Date(2030, time.January, 7, 10, 0, 0, 0, time.UTC)
+	general := todoFixture()
+	general.VolunteersNeeded, general.Time = 1, monday
+	community := todoFixture()
+	community.VolunteerType, community.VolunteersNeeded, community.Time = "community", 2, monday.AddDate(0, 0, 1)
+	for _, todo := range []services.Todo{general, community} {
+		id := s.createTodo(todo, orgCookie)
+		join := request{method: http.MethodPost, path: "/api/v2/todos/" + id + "/volunteers", body: services.Volunteer{VolunteerID: adaID}, cookies: []*http.Cookie{orgCookie}}
+		expectStatus(t, s.do(join), http.StatusCreated)
+	}
+
+	get := func(path string) request {
+		return request{method: http.MethodGet, path: "/api/v1/analytics/org/" + path, cookies: []*http.Cookie{orgCookie}}
+	}
+
+	rec := s.do(get("status?orgName=Helping+Hands&bucket=week"))
+	expectStatus(t, rec, http.StatusOK)
+	week := time.Date(2030, time.January, 6, 0, 0, 0, 0, time.UTC)
+	if got := decode[[]services.StatusBucket](t, rec); len(got) != 1 || !got[0].Bucket.Equal(week) || got[0].Open != 2 || got[0].Total != 2 {
+		t.Errorf("got weekly status %+v", got)
+	}
+	if got := decode[[]services.StatusBucket](t, s.do(get("status?orgName=Helping+Hands&bucket=day"))); len(got) != 2 || !got[0].Bucket.Before(got[1].Bucket) {
+		t.Errorf("got daily status %+v", got)
+	}
+
+	fill := decode[services.FillStats](t, s.do(get("fill?orgName=Helping+Hands")))
+	if fill.Tasks != 2 || fill.FilledTasks != 1 || fill.AverageFillRate != 0.75 || fill.AverageTimeToFill < 0 || fill.AverageTimeToFill > 1 {
+		t.Errorf("got fill stats %+v", fill)
+	}
+	if fill := decode[services.FillStats](t, s.do(get("fill?orgName=Helping+Hands&from=2030-01-08"))); fill.Tasks != 1 || fill.FilledTasks != 0 {
+		t.Errorf("got fill stats %+v from the second day", fill)
+	}
+
+	types := decode[[]services.VolTypeCount](t, s.do(get("voltypes?orgName=Helping+Hands")))
+	want := []services.VolTypeCount{{VolunteerType: "community", Volunteers: 1, Joins: 1}, {VolunteerType: "general", Volunteers: 1, Joins: 1}}
+	if len(types) != len(want) || types[0] != want[0] || types[1] != want[1] {
+		t.Errorf("got volunteer types %+v, want %+v", types, want)
+	}
+
+	repeat := decode[services.RepeatStats](t, s.do(get("repeat?orgName=Helping+Hands")))
+	if repeat != (services.RepeatStats{Volunteers: 1, RepeatVolunteers: 1, RepeatVolunteerRate: 1}) {
+		t.Errorf("got repeat stats %+v", repeat)
+	}
+
+	// Another organisation taking the same name only sees its own todos
+	twin := organisationFixture()
+	twin.Email, twin.ContactNumber = "twin@example.com", "+447911000004"
+	_, twinCookie := s.signupAndLogin(twin)
+	rec = s.do(request{method: http.MethodGet, path: "/api/v1/analytics/org/fill?orgName=Helping+Hands", cookies: []*http.Cookie{twinCookie}})
+	expectStatus(t, rec, http.StatusOK)
+	if fill := decode[services.FillStats](t, rec); fill.Tasks != 0 {
+		t.Errorf("got fill stats %+v for an organisation sharing the name", fill)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/volunteerService-backend/services"
+)
+
+func (h *Handlers) getAvailability(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	availability, err := h.models.GetAvailability(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(availability)
+}
+
+func (h *Handlers) setAvailability(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.models.AuthorizeUser(r.Context(), currentUserEmail(r), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var availability services.Availability
+	err := json.NewDecoder(r.Body).Decode(&availability)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = h.models.SetAvailability(r.Context(), id, availability)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeResponse(w, Response{
+		Msg:  "Successfully updated availability",
+		Code: 200,
+	})
+}
+
+// filterAvailableFor narrows todos down to those fitting the availability of the user
+// given in the 'availableFor' query parameter, if any
+func (h *Handlers) filterAvailableFor(r *http.Request, todos []services.Todo) ([]services.Todo, error) {
+	userID := r.URL.Query().Get("availableFor")
+	if userID == "" {
+		return todos, nil
+	}
+
+	return h.models.FilterByAvailability(r.Context(), todos, userID)
+}
+
+// checkVolunteerSchedules checks every volunteer being added to the todo for schedule conflicts
+func (h *Handlers) checkVolunteerSchedules(ctx context.Context, id string, volunteers []services.Volunteer) ([]string, error) {
+	if len(volunteers) == 0 {
+		return nil, nil
+	}
+
+	existing, err := h.todos.GetTodoById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, volunteer := range volunteers {
+		name := volunteer.VolunteerName
+		if name == "" {
+			name = volunteer.VolunteerID
+		}
+
+		volunteerProblems, err := h.models.CheckSchedule(ctx, existing, volunteer.VolunteerID)
+		if err != nil {
+			return nil, err
+		}
+		for _, problem := range volunteerProblems {
+			problems = append(problems, name+": "+problem)
+		}
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/volunteerService-backend/services"
+)
+
+func TestAvailability(t *testing.T) {
+	s := newTestServer(t)
+	adaID, adaCookie := s.signupAndLogin(volunteerFixture())
+	orgID, _ := s.signupAndLogin(organisationFixture())
+
+	rec := s.get("/api/v1/users/" + adaID + "/availability")
+	expectStatus(t, rec, http.StatusOK)
+	if a := decode[services.Availability](t, rec); len(a.Windows) != 0 {
+		t.Fatalf("new user has availability %+v", a)
+	}
+
+	tests := []struct {
+		name       string
+		userID     string
+		cookie     *http.Cookie
+		body       interface{}
+		wantStatus int
+		wantFields []string
+	}{
+		{"weekday mornings", adaID, adaCookie, services.Availability{
+			Timezone: "Europe/London",
+			Windows:  []services.AvailabilityWindow{{Day: time.Monday, Start: "09:00", End: "12:00"}},
+		}, http.StatusOK, nil},
+		{"invalid window", adaID, adaCookie, services.Availability{
+			Timezone: "Mars/Olympus",
+			Windows:  []services.AvailabilityWindow{{Day: 9, Start: "12:00", End: "09:00"}},
+		}, http.StatusBadRequest, []string{"timezone", "windows[0].day", "windows[0]"}},
+		{"malformed body", adaID, adaCookie, "{", http.StatusBadRequest, nil},
+		{"anonymous", adaID, nil, services.Availability{}, http.StatusUnauthorized, nil},
+		{"someone else", orgID, adaCookie, services.Availability{}, http.StatusForbidden, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/api/v1/users/" + tt.userID + "/availability"
+			req := request{method: http.MethodPut, path: path, body: tt.body}
+			if tt.cookie != nil {
+				req.cookies = []*http.Cookie{tt.cookie}
+			}
+			rec := s.do(req)
+			if tt.wantStatus != http.StatusOK {
+				p := expectProblem(t, rec, tt.wantStatus)
+				for _, field := range tt.wantFields {
+					if !hasFieldError(p, field) {
+						t.Errorf("missing error for %s in %+v", field, p.Errors)
+					}
+				}
+				return
+			}
+
+			expectStatus(t, rec, http.StatusOK)
+			stored := decode[services.Availability](t, s.get(path))
+			if stored.Timezone != "Europe/London" || len(stored.Windows) != 1 {
+				t.Errorf("got stored availability %+v", stored)
+			}
+		})
+	}
+}
+
+func TestUpdateTodoScheduleConflict(t *testing.T) {
+	s := newTestServer(t)
+	adaID, _ := s.signupAndLogin(volunteerFixture())
+
+	start := time.Date(2030, time.January, 7, 10, 0, 0, 0, time.UTC) // a Monday
+	first := todoFixture()
+	first.Time = start
+	firstID := s.createTodo(first, nil)
+	second := todoFixture()
+	second.Task = "Serve lunch"
+	second.Time = start.Add(30 * time.Minute)
+	secondID := s.createTodo(second, nil)
+
+	join := services.Todo{Task: "x", Volunteer: []services.Volunteer{{VolunteerID: adaID}}}
+
+	expectStatus(t, s.do(request{method: http.MethodPut, path: "/api/v1/todos/update/" + firstID, body: join}), http.StatusOK)
+
+	rec := s.do(request{method: http.MethodPut, path: "/api/v1/todos/update/" + secondID, body: join})
+	p := expectProblem(t, rec, http.StatusConflict)
+	if len(p.Conflicts) == 0 {
+		t.Error("conflict response lists no conflicts")
+	}
+
+	rec = s.do(request{method: http.MethodPut, path: "/api/v1/todos/update/" + secondID + "?onConflict=warn", body: join})
+	expectStatus(t, rec, http.StatusOK)
+	if res := decode[Response](t, rec); len(res.Warnings) == 0 {
+		t.Error("warn mode returned no warnings")
+	}
+}
+
+func TestAvailableForFilter(t *testing.T) {
+	s := newTestServer(t)
+	adaID, adaCookie := s.signupAndLogin(volunteerFixture())
+
+	monday := time.Date(2030, time.January, 7, 10, 0, 0, 0, time.UTC)
+	fits := todoFixture()
+	fits.Time = monday
+	s.createTodo(fits, nil)
+	clashes := todoFixture()
+	clashes.Task = "Night shift"
+	clashes.Time = monday.Add(12 * time.Hour)
+	s.createTodo(clashes, nil)
+
+	availability := services.Availability{
+		Timezone: "UTC",
+		Windows:  []services.AvailabilityWindow{{Day: time.Monday, Start: "09:00", End: "17:00"}},
+	}
+	expectStatus(t, s.do(request{
+		method:  http.MethodPut,
+		path:    "/api/v1/users/" + adaID + "/availability",
+		body:    availability,
+		cookies: []*http.Cookie{adaCookie},
+	}), http.StatusOK)
+
+	todos := decode[[]services.Todo](t, s.get("/api/v1/todos?availableFor="+adaID))
+	if len(todos) != 1 || todos[0].Task != fits.Task {
+		t.Errorf("got %+v, want only %q", todos, fits.Task)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/volunteerService-backend/services"
+)
+
+func TestLoginCookies(t *testing.T) {
+	s := newTestServer(t)
+	s.signup(volunteerFixture())
+
+	rec := s.do(request{
+		method: http.MethodPost,
+		path:   "/api/v1/login",
+		body:   LoginRequest{Email: volunteerFixture().Email, Password: testPassword},
+	})
+	expectStatus(t, rec, http.StatusOK)
+
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range rec.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	auth, csrf := cookies[services.AuthCookie], cookies[services.CSRFCookie]
+	if auth == nil || csrf == nil {
+		t.Fatalf("got cookies %v, want the auth and CSRF cookies", cookies)
+	}
+	if auth.SameSite != http.SameSiteLaxMode || csrf.SameSite != http.SameSiteLaxMode {
+		t.Errorf("got SameSite %v and %v, want lax", auth.SameSite, csrf.SameSite)
+	}
+	if !auth.HttpOnly || csrf.HttpOnly {
+		t.Error("want only the auth cookie hidden from scripts")
+	}
+	if csrf.Value != s.models.CSRFToken(auth.Value) || rec.Header().Get(services.CSRFHeader) != csrf.Value {
+		t.Errorf("got CSRF cookie %q and header %q, want the session's token", csrf.Value, rec.Header().Get(services.CSRFHeader))
+	}
+}
+
+func TestCSRF(t *testing.T) {
+	s := newTestServer(t)
+	_, cookie := s.signupAndLogin(organisationFixture())
+
+	// create sends a todo as the cookie's user, with the given CSRF and Authorization headers
+	create := func(csrfToken, authorization string) int {
+		body, _ := json.Marshal(todoFixture())
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/todos/create", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.AddCookie(cookie)
+		if csrfToken != "" {
+			r.Header.Set(services.CSRFHeader, csrfToken)
+		}
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	tests := []struct {
+		name          string
+		csrfToken     string
+		authorization string
+		want          int
+	}{
+		{"missing token", "", "", http.StatusForbidden},
+		{"token of another session", s.models.CSRFToken("another-session"), "", http.StatusForbidden},
+		{"session token", s.models.CSRFToken(cookie.Value), "", http.StatusOK},
+		{"bearer token", "", "Bearer " + cookie.Value, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := create(tt.csrfToken, tt.authorization); got != tt.want {
+				t.Errorf("got status %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// Reads need no token
+	expectStatus(t, s.do(request{method: http.MethodGet, path: "/api/v2/users/me", cookies: []*http.Cookie{cookie}, noCSRF: true}), http.StatusOK)
+
+	// Neither do anonymous writes, which act as no one
+	expectStatus(t, s.do(request{method: http.MethodPost, path: "/api/v1/todos/create", body: todoFixture()}), http.StatusOK)
+}
+
+func TestCORS(t *testing.T) {
+	s := newTestServer(t)
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodOptions, "/api/v2/todos", nil)
+		r.Header.Set("Origin", origin)
+		r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		r.Header.Set("Access-Control-Request-Headers", "Content-Type, "+services.CSRFHeader)
+
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, r)
+		return rec
+	}
+
+	rec := preflight("http://localhost:3000")
+	if rec.Header().Get("Access-Control-Allow-Origin") != "http://localhost:3000" || rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Errorf("got headers %v for an allowed origin", rec.Header())
+	}
+
+	if rec := preflight("https://evil.example.com"); rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("got Access-Control-Allow-Origin %q for another origin", rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/services"
+)
+
+// GraphQLRequest is the body of a GraphQL query
+type GraphQLRequest struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse is the result of a GraphQL query
+type GraphQLResponse struct {
+	Data   interface{}                `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// defaultListSize is how many items a list field is expected to return when estimating
+// the complexity of a query, unless its 'limit' argument, or that argument's default, says otherwise
+const defaultListSize = 10
+
+// graphQLEndpoint executes GraphQL queries against schema, rejecting queries over the limits
+type graphQLEndpoint struct {
+	schema graphql.Schema
+	models *services.Models
+	limits config.GraphQLConfig
+}
+
+// newGraphQLEndpoint serves the GraphQL schema of h. It panics if the schema is invalid,
+// which can only be a programming error.
+func newGraphQLEndpoint(h *Handlers, limits config.GraphQLConfig) *graphQLEndpoint {
+	schema, err := newGraphQLSchema(h)
+	if err != nil {
+		panic(fmt.Sprintf("building GraphQL schema: %v", err))
+	}
+
+	return &graphQLEndpoint{schema: schema, models: h.models, limits: limits}
+}
+
+// writeGraphQL sends a GraphQL response with the given status
+func writeGraphQL(w http.ResponseWriter, status int, res GraphQLResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+// requestErrors reports a query that could not be run at all, with a code in each error's extensions
+func requestErrors(w http.ResponseWriter, code string, errs ...error) {
+	res := GraphQLResponse{}
+	for _, err := range errs {
+		formatted := gqlerrors.FormatError(err)
+		formatted.Extensions = map[string]interface{}{"code": code}
+		res.Errors = append(res.Errors, formatted)
+	}
+	writeGraphQL(w, http.StatusBadRequest, res)
+}
+
+// ServeHTTP runs a query sent as a JSON POST body. Parse errors, validation errors and queries
+// over the depth or complexity limits are rejected with 400 before anything is resolved.
+func (e *graphQLEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req GraphQLRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || strings.TrimSpace(req.Query) == "" {
+		requestErrors(w, "BAD_REQUEST", fmt.Errorf("request body must be JSON with a 'query'"))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query)})})
+	if err != nil {
+		requestErrors(w, "GRAPHQL_PARSE_FAILED", err)
+		return
+	}
+
+	validation := graphql.ValidateDocument(&e.schema, doc, nil)
+	if !validation.IsValid {
+		res := GraphQLResponse{Errors: validation.Errors}
+		for i := range res.Errors {
+			res.Errors[i].Extensions = map[string]interface{}{"code": "GRAPHQL_VALIDATION_FAILED"}
+		}
+		writeGraphQL(w, http.StatusBadRequest, res)
+		return
+	}
+
+	depth, complexity := measureQuery(&e.schema, doc, req.Variables)
+	if depth > e.limits.MaxDepth {
+		requestErrors(w, "QUERY_TOO_DEEP", fmt.Errorf("query depth %d exceeds the limit of %d", depth, e.limits.MaxDepth))
+		return
+	}
+	if complexity > e.limits.MaxComplexity {
+		requestErrors(w, "QUERY_TOO_COMPLEX", fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, e.limits.MaxComplexity))
+		return
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(r.Context(), e.models),
+	})
+
+	writeGraphQL(w, http.StatusOK, GraphQLResponse{Data: result.Data, Errors: result.Errors})
+}
+
+// measureQuery returns the deepest nesting of fields in doc and its estimated complexity:
+// every field costs 1, and the fields selected below a list are counted once per expected item.
+// Introspection fields are free so tooling can always fetch the schema. Arguments given as
+// variables are read from variables, or from the variable's default.
+func measureQuery(schema *graphql.Schema, doc *ast.Document, variables map[string]interface{}) (depth, complexity int) {
+	m := queryMeasurer{schema: schema, fragments: map[string]*ast.FragmentDefinition{}, variables: variables}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			m.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			m.variableDefaults = map[string]ast.Value{}
+			for _, v := range op.VariableDefinitions {
+				if v.DefaultValue != nil {
+					m.variableDefaults[v.Variable.Name.Value] = v.DefaultValue
+				}
+			}
+			d, c := m.selectionSet(op.SelectionSet, schema.QueryType(), 1, map[string]bool{})
+			depth = max(depth, d)
+			complexity += c
+		}
+	}
+
+	return depth, complexity
+}
+
+type queryMeasurer struct {
+	schema           *graphql.Schema
+	fragments        map[string]*ast.FragmentDefinition
+	variables        map[string]interface{}
+	variableDefaults map[string]ast.Value
+}
+
+// selectionSet measures set, selected on parent at the given depth. visiting holds the
+// fragments being expanded, so a cycle (which validation rejects anyway) can't recurse forever.
+func (m queryMeasurer) selectionSet(set *ast.SelectionSet, parent *graphql.Object, depth int, visiting map[string]bool) (maxDepth, cost int) {
+	if set == nil || parent == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, c = m.field(s, parent, depth, visiting)
+		case *ast.InlineFragment:
+			on := parent
+			if s.TypeCondition != nil {
+				on, _ = m.schema.Type(s.TypeCondition.Name.Value).(*graphql.Object)
+			}
+			d, c = m.selectionSet(s.SelectionSet, on, depth, visiting)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			fragment := m.fragments[name]
+			if fragment == nil || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			on, _ := m.schema.Type(fragment.TypeCondition.Name.Value).(*graphql.Object)
+			d, c = m.selectionSet(fragment.SelectionSet, on, depth, visiting)
+			delete(visiting, name)
+		}
+		maxDepth = max(maxDepth, d)
+		cost += c
+	}
+
+	return maxDepth, cost
+}
+
+func (m queryMeasurer) field(field *ast.Field, parent *graphql.Object, depth int, visiting map[string]bool) (maxDepth, cost int) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+	def := parent.Fields()[field.Name.Value]
+	if def == nil {
+		return depth, 1
+	}
+
+	fieldType := def.Type
+	if nonNull, ok := fieldType.(*graphql.NonNull); ok {
+		fieldType = nonNull.OfType
+	}
+	items := 1
+	if list, ok := fieldType.(*graphql.List); ok {
+		items = m.listSize(field, def)
+		fieldType = list.OfType
+		if nonNull, ok := fieldType.(*graphql.NonNull); ok {
+			fieldType = nonNull.OfType
+		}
+	}
+
+	object, ok := fieldType.(*graphql.Object)
+	if !ok || field.SelectionSet == nil {
+		return depth, 1
+	}
+
+	childDepth, childCost := m.selectionSet(field.SelectionSet, object, depth+1, visiting)
+	return max(depth, childDepth), 1 + items*childCost
+}
+
+// listSize is the number of items a list field is expected to return, taken from its
+// 'limit' argument when that is given, or else from the argument's default
+func (m queryMeasurer) listSize(field *ast.Field, def *graphql.FieldDefinition) int {
+	for _, arg := range field.Arguments {
+		if n, ok := m.intValue(arg.Value); ok && n > 0 && arg.Name.Value == "limit" {
+			return n
+		}
+	}
+	for _, arg := range def.Args {
+		if n, ok := arg.DefaultValue.(int); ok && n > 0 && arg.Name() == "limit" {
+			return n
+		}
+	}
+	return defaultListSize
+}
+
+// intValue returns the integer value is, when it is an integer literal or a variable holding one
+func (m queryMeasurer) intValue(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		return n, err == nil
+	case *ast.Variable:
+		// Variables decoded from JSON hold float64s
+		switch n := m.variables[v.Name.Value].(type) {
+		case float64:
+			return int(n), n == float64(int(n))
+		case int:
+			return n, true
+		case nil:
+			if def, ok := m.variableDefaults[v.Name.Value]; ok {
+				return m.intValue(def)
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/services"
+)
+
+// graphQLError is a resolver error that is safe to show to clients,
+// with a machine readable code in its extensions
+type graphQLError struct {
+	msg  string
+	code string
+}
+
+func (e graphQLError) Error() string {
+	return e.msg
+}
+
+func (e graphQLError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// resolverError maps an error returned by the services to a graphQLError, like writeError
+// does for REST. Anything that isn't a domain error is logged and reported generically.
+func resolverError(p graphql.ResolveParams, err error) error {
+	codes := []struct {
+		kind error
+		code string
+	}{
+		{services.ErrNotFound, "NOT_FOUND"},
+		{services.ErrConflict, "CONFLICT"},
+		{services.ErrValidation, "BAD_USER_INPUT"},
+		{services.ErrForbidden, "FORBIDDEN"},
+		{services.ErrUnauthorized, "UNAUTHENTICATED"},
+		{services.ErrUnsupported, "NOT_IMPLEMENTED"},
+	}
+
+	var domainErr *services.Error
+	for _, c := range codes {
+		if errors.Is(err, c.kind) && errors.As(err, &domainErr) {
+			return graphQLError{msg: domainErr.Error(), code: c.code}
+		}
+	}
+	if services.IsTimeout(err) {
+		logging.FromContext(p.Context).Warn("Database timeout", "error", err, "field", p.Info.FieldName)
+		return graphQLError{msg: "The database took too long to respond", code: "TIMEOUT"}
+	}
+
+	logging.FromContext(p.Context).Error("Internal error", "error", err, "field", p.Info.FieldName)
+	return graphQLError{msg: "An unexpected error occurred", code: "INTERNAL_SERVER_ERROR"}
+}
+
+// timeField resolves a time that is unset when zero
+func timeField(get func(source interface{}) time.Time) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		t := get(p.Source)
+		if t.IsZero() {
+			return nil, nil
+		}
+		return t, nil
+	}
+}
+
+// loadUser returns a thunk resolving the user with id through the request's batch loader.
+// The thunk returns nil when id is empty or no such user exists.
+func loadUser(p graphql.ResolveParams, id string) func() (interface{}, error) {
+	if id == "" {
+		return func() (interface{}, error) { return nil, nil }
+	}
+
+	load := loadersFrom(p.Context).users.load(p.Context, id)
+	return func() (interface{}, error) {
+		user, err := load()
+		if err != nil {
+			return nil, resolverError(p, err)
+		}
+		return user, nil
+	}
+}
+
+// newGraphQLSchema builds the GraphQL schema, resolving todos through h and everything else
+// through the services. Lookups of users referenced by todos are batched per request.
+func newGraphQLSchema(h *Handlers) (graphql.Schema, error) {
+	nonNullString := graphql.NewNonNull(graphql.String)
+
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "A volunteer or organisation account",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"firstName": &graphql.Field{Type: graphql.String},
+			"lastName":  &graphql.Field{Type: graphql.String},
+			"email":     &graphql.Field{Type: graphql.String},
+			"contactNo": &graphql.Field{Type: graphql.String},
+			"userType":  &graphql.Field{Type: graphql.String},
+			"volType":   &graphql.Field{Type: graphql.String},
+			"orgName":   &graphql.Field{Type: graphql.String},
+			"orgType":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	volunteerType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Volunteer",
+		Description: "A volunteer signed up for a todo",
+		Fields: graphql.Fields{
+			"volunteerId":   &graphql.Field{Type: graphql.ID},
+			"volunteerName": &graphql.Field{Type: graphql.String},
+			"joinedAt": &graphql.Field{Type: graphql.DateTime, Resolve: timeField(func(source interface{}) time.Time {
+				return source.(services.Volunteer).JoinedAt
+			})},
+			"user": &graphql.Field{
+				Type:        userType,
+				Description: "The volunteer's profile, null for volunteers only known by name",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadUser(p, p.Source.(services.Volunteer).VolunteerID), nil
+				},
+			},
+		},
+	})
+
+	fieldChangeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "FieldChange",
+		Fields: graphql.Fields{
+			"field": &graphql.Field{Type: nonNullString},
+			"old":   &graphql.Field{Type: graphql.String, Resolve: changeValue(func(c services.FieldChange) interface{} { return c.Old })},
+			"new":   &graphql.Field{Type: graphql.String, Resolve: changeValue(func(c services.FieldChange) interface{} { return c.New })},
+		},
+	})
+
+	historyEntryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "HistoryEntry",
+		Fields: graphql.Fields{
+			"id":     &graphql.Field{Type: graphql.ID},
+			"action": &graphql.Field{Type: nonNullString},
+			"actor":  &graphql.Field{Type: graphql.String},
+			"timestamp": &graphql.Field{Type: graphql.DateTime, Resolve: timeField(func(source interface{}) time.Time {
+				return source.(services.HistoryEntry).Timestamp
+			})},
+			"changes": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(fieldChangeType))},
+		},
+	})
+
+	organisationType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Organisation",
+		Description: "The public profile of an organisation",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"name":      &graphql.Field{Type: nonNullString},
+			"type":      &graphql.Field{Type: graphql.String},
+			"email":     &graphql.Field{Type: graphql.String},
+			"contactNo": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	todoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Todo",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"task":        &graphql.Field{Type: nonNullString},
+			"description": &graphql.Field{Type: graphql.String},
+			"completed":   &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"volunteersNeeded": &graphql.Field{Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(services.Todo).VolunteersNeeded, nil
+			}},
+			"volType": &graphql.Field{Type: graphql.String},
+			"orgType": &graphql.Field{Type: graphql.String},
+			"orgName": &graphql.Field{Type: graphql.String},
+			"time": &graphql.Field{Type: graphql.DateTime, Resolve: timeField(func(source interface{}) time.Time {
+				return source.(services.Todo).Time
+			})},
+			"endTime": &graphql.Field{Type: graphql.DateTime, Resolve: timeField(func(source interface{}) time.Time {
+				return source.(services.Todo).EndTime
+			})},
+			"createdAt": &graphql.Field{Type: graphql.DateTime, Resolve: timeField(func(source interface{}) time.Time {
+				return source.(services.Todo).CreatedAt
+			})},
+			"organisation": &graphql.Field{
+				Type:        organisationType,
+				Description: "The organisation running the todo, null for todos only carrying its name",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					load := loadUser(p, p.Source.(services.Todo).OrganisationID)
+					return func() (interface{}, error) {
+						user, err := load()
+						if user == nil || err != nil {
+							return nil, err
+						}
+						return user.(services.User).OrganisationProfile(), nil
+					}, nil
+				},
+			},
+			"volunteers": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(volunteerType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					volunteers := p.Source.(services.Todo).Volunteer
+					if volunteers == nil {
+						volunteers = []services.Volunteer{}
+					}
+					return volunteers, nil
+				},
+			},
+		},
+	})
+
+	// The history of a todo is restricted to its organisation, as in REST
+	todoType.AddFieldConfig("history", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(historyEntryType))),
+		Description: "Changes to the todo, newest first. Only visible to the owning organisation.",
+		Args: graphql.FieldConfigArgument{
+			"page":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+			"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultHistoryLimit},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			todo := p.Source.(services.Todo)
+			if err := h.models.AuthorizeHistory(p.Context, currentUserEmailFrom(p.Context), todo.ID); err != nil {
+				return nil, resolverError(p, err)
+			}
+
+			page, limit := historyPaging(p.Args["page"].(int), p.Args["limit"].(int))
+
+			entries, _, err := h.models.GetTodoHistory(p.Context, todo.ID, page, limit)
+			if err != nil {
+				return nil, resolverError(p, err)
+			}
+			return entries, nil
+		},
+	})
+
+	organisationType.AddFieldConfig("todos", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(todoType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			todos, err := h.todos.GetTodosByOrg(p.Context, p.Source.(services.Organisation).Name)
+			if err != nil {
+				return nil, resolverError(p, err)
+			}
+			return todos, nil
+		},
+	})
+
+	idArg := graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"todos": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(todoType))),
+				Description: "Todos, optionally filtered by organisation name or volunteer type, and by a user's availability",
+				Args: graphql.FieldConfigArgument{
+					"orgName":      &graphql.ArgumentConfig{Type: graphql.String},
+					"volType":      &graphql.ArgumentConfig{Type: graphql.String},
+					"availableFor": &graphql.ArgumentConfig{Type: graphql.ID},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					orgName, _ := p.Args["orgName"].(string)
+					volType, _ := p.Args["volType"].(string)
+
+					var todos []services.Todo
+					var err error
+					switch {
+					case orgName != "":
+						todos, err = h.todos.GetTodosByOrg(p.Context, orgName)
+					case volType != "":
+						todos, err = h.todos.GetTodosByVolType(p.Context, volType)
+					default:
+						todos, err = h.todos.GetAllTodos(p.Context)
+					}
+					if err != nil {
+						return nil, resolverError(p, err)
+					}
+
+					if userID, _ := p.Args["availableFor"].(string); userID != "" {
+						todos, err = h.models.FilterByAvailability(p.Context, todos, userID)
+						if err != nil {
+							return nil, resolverError(p, err)
+						}
+					}
+					return todos, nil
+				},
+			},
+			"todo": &graphql.Field{
+				Type: todoType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					todo, err := h.todos.GetTodoById(p.Context, p.Args["id"].(string))
+					if errors.Is(err, services.ErrNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, resolverError(p, err)
+					}
+					return todo, nil
+				},
+			},
+			"user": &graphql.Field{
+				Type: userType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadUser(p, p.Args["id"].(string)), nil
+				},
+			},
+			"users": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+				Args: graphql.FieldConfigArgument{
+					"ids": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var ids []string
+					for _, id := range p.Args["ids"].([]interface{}) {
+						ids = append(ids, id.(string))
+					}
+					users, err := h.models.GetUsersByID(p.Context, ids)
+					if err != nil {
+						return nil, resolverError(p, err)
+					}
+					return users, nil
+				},
+			},
+			"organisations": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(organisationType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					organisations, err := h.models.GetOrganisations(p.Context)
+					if err != nil {
+						return nil, resolverError(p, err)
+					}
+					return organisations, nil
+				},
+			},
+			"organisation": &graphql.Field{
+				Type: organisationType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					organisation, err := h.models.GetOrganisationById(p.Context, p.Args["id"].(string))
+					if errors.Is(err, services.ErrNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, resolverError(p, err)
+					}
+					return organisation, nil
+				},
+			},
+			"me": &graphql.Field{
+				Type:        graphql.NewNonNull(userType),
+				Description: "The authenticated user",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					email := currentUserEmailFrom(p.Context)
+					if email == "" {
+						return nil, resolverError(p, services.Unauthorized("Authentication required"))
+					}
+					user, err := h.models.GetUserByEmail(p.Context, email)
+					if err != nil {
+						return nil, resolverError(p, err)
+					}
+					return user, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// changeValue resolves the old or new value of a FieldChange as a string
+func changeValue(get func(services.FieldChange) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		value := get(p.Source.(services.FieldChange))
+		if value == nil {
+			return nil, nil
+		}
+		return fmt.Sprint(value), nil
+	}
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/volunteerService-backend/services"
+)
+
+// graphQL runs query as the user of cookie, which may be nil
+func (s *testServer) graphQL(query string, cookie *http.Cookie) (int, GraphQLResponse) {
+	s.t.Helper()
+
+	req := request{method: http.MethodPost, path: "/graphql", body: GraphQLRequest{Query: query}}
+	if cookie != nil {
+		req.cookies = []*http.Cookie{cookie}
+	}
+	rec := s.do(req)
+	return rec.Code, decode[GraphQLResponse](s.t, rec)
+}
+
+// errorCode returns the code of the first error in res, or "" if there is none
+func errorCode(res GraphQLResponse) string {
+	if len(res.Errors) == 0 {
+		return ""
+	}
+	code, _ := res.Errors[0].Extensions["code"].(string)
+	return code
+}
+
+func TestGraphQLNestedResolution(t *testing.T) {
+	s := newTestServer(t)
+	orgID, _ := s.signupAndLogin(organisationFixture())
+	adaID, _ := s.signupAndLogin(volunteerFixture())
+
+	todo := todoFixture()
+	todoID := s.createTodo(todo, nil)
+	join := services.Todo{Task: todo.Task, Volunteer: []services.Volunteer{{VolunteerID: adaID}}}
+	expectStatus(t, s.do(request{method: http.MethodPut, path: "/api/v1/todos/update/" + todoID, body: join}), http.StatusOK)
+
+	status, res := s.graphQL(`{
+		todos {
+			id
+			task
+			organisation { id name }
+			volunteers { volunteerId user { firstName email } }
+		}
+	}`, nil)
+	if status != http.StatusOK || len(res.Errors) > 0 {
+		t.Fatalf("got status %d, errors %+v", status, res.Errors)
+	}
+
+	todos := res.Data.(map[string]interface{})["todos"].([]interface{})
+	if len(todos) != 1 {
+		t.Fatalf("got todos %+v", todos)
+	}
+	got := todos[0].(map[string]interface{})
+	if org := got["organisation"].(map[string]interface{}); org["id"] != orgID || org["name"] != "Helping Hands" {
+		t.Errorf("got organisation %+v", org)
+	}
+	volunteers := got["volunteers"].([]interface{})
+	if len(volunteers) != 1 {
+		t.Fatalf("got volunteers %+v", volunteers)
+	}
+	user := volunteers[0].(map[string]interface{})["user"].(map[string]interface{})
+	if user["firstName"] != "Ada" || user["email"] != volunteerFixture().Email {
+		t.Errorf("got volunteer user %+v", user)
+	}
+}
+
+func TestGraphQLEndTime(t *testing.T) {
+	s := newTestServer(t)
+	start := time.Date(2030, time.January, 7, 9, 0, 0, 0, time.UTC)
+	open := todoFixture()
+	open.Time = start
+	bounded := todoFixture()
+	bounded.Time, bounded.EndTime = start, start.Add(3*time.Hour)
+	openID := s.createTodo(open, nil)
+	boundedID := s.createTodo(bounded, nil)
+
+	// A todo without an end has none, rather than the hour assumed for schedule checks
+	for id, want := range map[string]interface{}{openID: nil, boundedID: "2030-01-07T12:00:00Z"} {
+		status, res := s.graphQL(`{ todo(id: "`+id+`") { endTime } }`, nil)
+		if status != http.StatusOK || len(res.Errors) > 0 {
+			t.Fatalf("got status %d, errors %+v", status, res.Errors)
+		}
+		if got := res.Data.(map[string]interface{})["todo"].(map[string]interface{})["endTime"]; got != want {
+			t.Errorf("got endTime %v, want %v", got, want)
+		}
+	}
+}
+
+func TestGraphQLAuthorization(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	_, adaCookie := s.signupAndLogin(volunteerFixture())
+	todoID := s.createTodo(todoFixture(), orgCookie)
+
+	history := `{ todo(id: "` + todoID + `") { history { action } } }`
+	tests := []struct {
+		name     string
+		query    string
+		cookie   *http.Cookie
+		wantCode string
+	}{
+		{"me anonymous", `{ me { email } }`, nil, "UNAUTHENTICATED"},
+		{"me", `{ me { email } }`, adaCookie, ""},
+		{"history anonymous", history, nil, "UNAUTHENTICATED"},
+		{"history of another organisation", history, adaCookie, "FORBIDDEN"},
+		{"history of own todo", history, orgCookie, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, res := s.graphQL(tt.query, tt.cookie)
+			if status != http.StatusOK {
+				t.Fatalf("got status %d", status)
+			}
+			if code := errorCode(res); code != tt.wantCode {
+				t.Errorf("got error code %q, want %q: %+v", code, tt.wantCode, res.Errors)
+			}
+		})
+	}
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode string
+	}{
+		{"too deep", `{ organisations { todos { organisation { todos { organisation { todos { organisation { todos { id } } } } } } } } }`, "QUERY_TOO_DEEP"},
+		{"too complex", `{ organisations { todos { volunteers { user { id firstName } } } } }`, "QUERY_TOO_COMPLEX"},
+		{"complex through fragments", `{ organisations { ...orgTodos } } fragment orgTodos on Organisation { todos { volunteers { user { id firstName } } } }`, "QUERY_TOO_COMPLEX"},
+		{"unknown field", `{ todos { password } }`, "GRAPHQL_VALIDATION_FAILED"},
+		{"syntax error", `{ todos {`, "GRAPHQL_PARSE_FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, res := s.graphQL(tt.query, nil)
+			if status != http.StatusBadRequest {
+				t.Errorf("got status %d, want 400", status)
+			}
+			if code := errorCode(res); code != tt.wantCode {
+				t.Errorf("got error code %q, want %q: %+v", code, tt.wantCode, res.Errors)
+			}
+		})
+	}
+
+	// Introspection is never limited so tooling can load the schema
+	if status, res := s.graphQL(`{ __schema { types { name fields { name type { name ofType { name ofType { name ofType { name } } } } } } } }`, nil); status != http.StatusOK {
+		t.Errorf("introspection got status %d: %+v", status, res.Errors)
+	}
+}
+
+func TestGraphQLHistoryPaging(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	todo := todoFixture()
+	todoID := s.createTodo(todo, orgCookie)
+	for i := 0; i < defaultHistoryLimit+1; i++ {
+		update := services.Todo{Task: todo.Task, Completed: i%2 == 0}
+		expectStatus(t, s.do(request{method: http.MethodPut, path: "/api/v1/todos/update/" + todoID, body: update, cookies: []*http.Cookie{orgCookie}}), http.StatusOK)
+	}
+
+	tests := []struct {
+		args     string
+		want     int
+		wantCode string
+	}{
+		{"", defaultHistoryLimit, ""},
+		// A limit below 1 falls back to the default, as in REST
+		{"(limit: 0)", defaultHistoryLimit, ""},
+		{"(limit: 500)", defaultHistoryLimit + 2, ""},
+		{"(page: 0, limit: 5)", 5, ""},
+		{fmt.Sprintf("(page: %d)", services.MaxHistoryPage+1), 0, "BAD_USER_INPUT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			_, res := s.graphQL(`{ todo(id: "`+todoID+`") { history`+tt.args+` { action } } }`, orgCookie)
+			if code := errorCode(res); code != tt.wantCode {
+				t.Fatalf("got error code %q, want %q: %+v", code, tt.wantCode, res.Errors)
+			}
+			if tt.wantCode != "" {
+				return
+			}
+			history := res.Data.(map[string]interface{})["todo"].(map[string]interface{})["history"].([]interface{})
+			if len(history) != tt.want {
+				t.Errorf("got %d entries, want %d", len(history), tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasureQuery(t *testing.T) {
+	s := newTestServer(t)
+	schema, err := newGraphQLSchema(New(s.models, nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A todo costs 1, plus its history: 1, and 1 per expected entry
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      int
+	}{
+		{"argument default", `{ todo(id: "x") { history { action } } }`, nil, 2 + defaultHistoryLimit},
+		{"literal", `{ todo(id: "x") { history(limit: 5) { action } } }`, nil, 7},
+		{"variable", `query($n: Int) { todo(id: "x") { history(limit: $n) { action } } }`, map[string]interface{}{"n": float64(500)}, 502},
+		{"variable default", `query($n: Int = 50) { todo(id: "x") { history(limit: $n) { action } } }`, nil, 52},
+		{"unset variable", `query($n: Int) { todo(id: "x") { history(limit: $n) { action } } }`, nil, 2 + defaultHistoryLimit},
+		{"no limit argument", `{ organisations { id } }`, nil, 1 + defaultListSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, complexity := measureQuery(&schema, doc, tt.variables); complexity != tt.want {
+				t.Errorf("got complexity %d, want %d", complexity, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchLoader(t *testing.T) {
+	var calls [][]string
+	loader := newBatchLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		calls = append(calls, keys)
+		results := map[string]int{}
+		for _, key := range keys {
+			if key != "missing" {
+				results[key] = len(key)
+			}
+		}
+		return results, nil
+	})
+
+	ctx := context.Background()
+	thunks := []func() (interface{}, error){
+		loader.load(ctx, "a"),
+		loader.load(ctx, "bb"),
+		loader.load(ctx, "a"),
+		loader.load(ctx, "missing"),
+	}
+
+	var got []interface{}
+	for _, thunk := range thunks {
+		value, err := thunk()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, value)
+	}
+
+	if len(calls) != 1 {
+		t.Fatalf("fetched %d times, want once: %v", len(calls), calls)
+	}
+	sort.Strings(calls[0])
+	if len(calls[0]) != 3 {
+		t.Errorf("fetched keys %v, want each key once", calls[0])
+	}
+	if got[0] != 1 || got[1] != 2 || got[2] != 1 || got[3] != nil {
+		t.Errorf("got %v", got)
+	}
+
+	// Keys already loaded come from the cache
+	if value, _ := loader.load(ctx, "bb")(); value != 2 || len(calls) != 1 {
+		t.Errorf("got %v after %d fetches", value, len(calls))
+	}
+}
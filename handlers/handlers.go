@@ -1,20 +1,64 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/ratelimit"
 	"github.com/volunteerService-backend/services"
+	"github.com/volunteerService-backend/worker"
 )
 
-var todo services.Todo
+// TodoService is the todo behaviour the handlers depend on, implemented by *services.Models
+type TodoService interface {
+	GetAllTodos(ctx context.Context) ([]services.Todo, error)
+	GetTodoById(ctx context.Context, id string) (services.Todo, error)
+	GetTodosByOrg(ctx context.Context, orgName string) ([]services.Todo, error)
+	GetTodosByOrganisationID(ctx context.Context, id, orgName string) ([]services.Todo, error)
+	GetTodosByVolType(ctx context.Context, volType string) ([]services.Todo, error)
+	InsertTodo(ctx context.Context, entry services.Todo, actor string) (string, error)
+	UpdateTodo(ctx context.Context, id string, entry services.Todo, actor string) error
+	DeleteTodo(ctx context.Context, id string, actor string) error
+	JoinTodo(ctx context.Context, id string, volunteer services.Volunteer, actor string) error
+	LeaveTodo(ctx context.Context, id, volunteerID, actor string) error
+	CloneTodo(ctx context.Context, id string, actor string) (string, error)
+}
+
+// Handlers serves the API with the services injected by main.
+// It holds no per-request state, so one value is safely shared by concurrent requests.
+type Handlers struct {
+	models   *services.Models
+	todos    TodoService
+	workers  *worker.Group
+	counters ratelimit.Store
+}
+
+// New returns the handlers for the given services, reporting the status of workers in health checks
+// and keeping rate limit counters in counters
+func New(models *services.Models, workers *worker.Group, counters ratelimit.Store) *Handlers {
+	return &Handlers{
+		models:   models,
+		todos:    models,
+		workers:  workers,
+		counters: counters,
+	}
+}
 
 // Response struct to standardize all responses
 type Response struct {
-	Msg  string `json:"msg"`
-	Code int    `json:"code"`
+	Msg      string   `json:"msg"`
+	Code     int      `json:"code"`
+	ID       string   `json:"id,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SignupResponse is returned by a successful signup
+type SignupResponse struct {
+	UserType string `json:"userType"`
 }
 
 // healthCheck - simple function to test api if its working
@@ -26,7 +70,7 @@ func healthCheck(w http.ResponseWriter, r *http.Request) {
 
 	jsonStr, err := json.Marshal(res)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(r.Context()).Error("Error encoding JSON response", "error", err)
 		return
 	}
 
@@ -35,17 +79,16 @@ func healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Write(jsonStr)
 }
 
-func getTodos(w http.ResponseWriter, r *http.Request) {
-	todos, err := todo.GetAllTodos()
+func (h *Handlers) getTodos(w http.ResponseWriter, r *http.Request) {
+	todos, err := h.todos.GetAllTodos(r.Context())
 	if err != nil {
-		log.Println(err)
-		res := Response{
-			Msg:  "Error retrieving todos",
-			Code: 500,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(res.Code)
-		json.NewEncoder(w).Encode(res)
+		writeError(w, r, err)
+		return
+	}
+
+	todos, err = h.filterAvailableFor(r, todos)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -54,19 +97,12 @@ func getTodos(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(todos)
 }
 
-func getTodoById(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) getTodoById(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	todo, err := todo.GetTodoById(id)
+	todo, err := h.todos.GetTodoById(r.Context(), id)
 	if err != nil {
-		log.Println(err)
-		res := Response{
-			Msg:  "Todo not found",
-			Code: 404,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(res.Code)
-		json.NewEncoder(w).Encode(res)
+		writeError(w, r, err)
 		return
 	}
 
@@ -75,32 +111,25 @@ func getTodoById(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(todo)
 }
 
-func getTodoByVol(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) getTodoByVol(w http.ResponseWriter, r *http.Request) {
 	// Retrieve the 'volType' query parameter from the URL
 	volType := r.URL.Query().Get("volType")
 	if volType == "" {
 		// If no 'volType' is provided, return an error response
-		errorRes := Response{
-			Msg:  "Missing 'volType' query parameter",
-			Code: 400,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(errorRes.Code)
-		json.NewEncoder(w).Encode(errorRes)
+		writeProblem(w, r, http.StatusBadRequest, "Missing 'volType' query parameter")
 		return
 	}
 
 	// Call the service method to get todos by VolunteerType
-	todos, err := todo.GetTodosByVolType(volType)
+	todos, err := h.todos.GetTodosByVolType(r.Context(), volType)
 	if err != nil {
-		log.Println("Error retrieving todos by volunteer type:", err)
-		errorRes := Response{
-			Msg:  "Error retrieving todos",
-			Code: 500,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(errorRes.Code)
-		json.NewEncoder(w).Encode(errorRes)
+		writeError(w, r, err)
+		return
+	}
+
+	todos, err = h.filterAvailableFor(r, todos)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -110,32 +139,25 @@ func getTodoByVol(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(todos)
 }
 
-func getTodoByOrg(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) getTodoByOrg(w http.ResponseWriter, r *http.Request) {
 	// Retrieve the 'orgName' query parameter from the URL
 	orgName := r.URL.Query().Get("orgName")
 	if orgName == "" {
 		// If no 'orgName' is provided, return an error response
-		errorRes := Response{
-			Msg:  "Missing 'orgName' query parameter",
-			Code: 400,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(errorRes.Code)
-		json.NewEncoder(w).Encode(errorRes)
+		writeProblem(w, r, http.StatusBadRequest, "Missing 'orgName' query parameter")
 		return
 	}
 
 	// Call the service method to get todos by OrganisationName
-	todos, err := todo.GetTodosByOrg(orgName)
+	todos, err := h.todos.GetTodosByOrg(r.Context(), orgName)
 	if err != nil {
-		log.Println("Error retrieving todos by organisation name:", err)
-		errorRes := Response{
-			Msg:  "Error retrieving todos",
-			Code: 500,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(errorRes.Code)
-		json.NewEncoder(w).Encode(errorRes)
+		writeError(w, r, err)
+		return
+	}
+
+	todos, err = h.filterAvailableFor(r, todos)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -145,186 +167,171 @@ func getTodoByOrg(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(todos)
 }
 
-func createTodo(w http.ResponseWriter, r *http.Request) {
-	err := json.NewDecoder(r.Body).Decode(&todo)
+func (h *Handlers) createTodo(w http.ResponseWriter, r *http.Request) {
+	var entry services.Todo
+	err := json.NewDecoder(r.Body).Decode(&entry)
 	if err != nil {
-		log.Fatal(err)
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return
 	}
 
-	err = todo.InsertTodo(todo)
+	id, err := h.todos.InsertTodo(r.Context(), entry, currentUserEmail(r))
 	if err != nil {
-		errorRes := Response{
-			Msg:  "Error creating todo",
-			Code: 304,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(errorRes.Code)
-		json.NewEncoder(w).Encode(errorRes)
+		writeError(w, r, err)
 		return
 	}
 
-	res := Response{
+	writeResponse(w, Response{
 		Msg:  "Successfully created todo",
 		Code: 200,
-	}
-
-	jsonStr, err := json.Marshal(res)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(res.Code)
-	w.Write(jsonStr)
+		ID:   id,
+	})
 }
 
-func updateTodo(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) updateTodo(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	err := json.NewDecoder(r.Body).Decode(&todo)
+
+	var entry services.Todo
+	err := json.NewDecoder(r.Body).Decode(&entry)
 	if err != nil {
-		log.Println(err)
-		res := Response{
-			Msg:  "Error decoding request",
-			Code: 400,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(res.Code)
-		json.NewEncoder(w).Encode(res)
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	_, err = todo.UpdateTodo(id, todo)
+	// Reject volunteers whose schedule clashes with the todo, unless only warnings were asked for
+	warnings, err := h.checkVolunteerSchedules(r.Context(), id, entry.Volunteer)
 	if err != nil {
-		errorRes := Response{
-			Msg:  err.Error(),
-			Code: 500,
-		}
-		jsonStr, err := json.Marshal(errorRes)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(errorRes.Code)
-		w.Write(jsonStr)
+		writeError(w, r, err)
 		return
 	}
-
-	res := Response{
-		Msg:  "Successfully updated todo",
-		Code: 200,
+	if len(warnings) > 0 && r.URL.Query().Get("onConflict") != "warn" {
+		p := newProblem(r, http.StatusConflict, "Volunteer schedule conflict")
+		p.Conflicts = warnings
+		sendProblem(w, p)
+		return
 	}
 
-	jsonStr, err := json.Marshal(res)
+	err = h.todos.UpdateTodo(r.Context(), id, entry, currentUserEmail(r))
 	if err != nil {
-		log.Fatal(err)
+		writeError(w, r, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(res.Code)
-	w.Write(jsonStr)
+	writeResponse(w, Response{
+		Msg:      "Successfully updated todo",
+		Code:     200,
+		Warnings: warnings,
+	})
 }
 
-func deleteTodo(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) deleteTodo(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := todo.DeleteTodo(id)
+	err := h.todos.DeleteTodo(r.Context(), id, currentUserEmail(r))
 	if err != nil {
-		errorRes := Response{
-			Msg:  "Error deleting todo",
-			Code: 304,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(errorRes.Code)
-		json.NewEncoder(w).Encode(errorRes)
+		writeError(w, r, err)
 		return
 	}
 
-	res := Response{
+	writeResponse(w, Response{
 		Msg:  "Successfully deleted todo",
 		Code: 200,
-	}
-
-	jsonStr, err := json.Marshal(res)
-	if err != nil {
-		log.Fatal(err)
-	}
+	})
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(res.Code)
-	w.Write(jsonStr)
+// LoginRequest is the body of a login
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
 // SignupHandler handles the signup request
-func SignupHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) SignupHandler(w http.ResponseWriter, r *http.Request) {
 	var user services.User
 
 	// Decode the request body into user struct
 	err := json.NewDecoder(r.Body).Decode(&user)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	// Call the Signup function
-	userType, err := services.Signup(user)
+	userType, err := h.models.Signup(r.Context(), user)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusConflict)
+		writeError(w, r, err)
 		return
 	}
 
 	// Send back the userType as the response
-	response := struct {
-		UserType string `json:"userType"`
-	}{
+	response := SignupResponse{
 		UserType: userType,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	err = json.NewEncoder(w).Encode(response)
-	if err != nil {
-		sendErrorResponse(w, "Error encoding JSON response", http.StatusInternalServerError)
-	}
+	json.NewEncoder(w).Encode(response)
 }
 
-// LoginHandler handles the login request
-func LoginHandler(w http.ResponseWriter, r *http.Request) {
-	var loginRequest struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-	}
+// LoginHandler handles the login request. Unless guard is nil, an email that keeps failing
+// to log in must wait longer before each attempt and is eventually locked out for a while.
+func (h *Handlers) LoginHandler(guard *ratelimit.LoginGuard) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req LoginRequest
 
-	// Decode request body into loginRequest
-	err := json.NewDecoder(r.Body).Decode(&loginRequest)
-	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
+		// Decode request body into req
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
+			return
+		}
 
-	// Call the Login function
-	_, err = services.Login(w, loginRequest.Email, loginRequest.Password)
-	if err != nil {
-		sendErrorResponse(w, err.Error(), http.StatusUnauthorized)
-		return
+		if guard != nil {
+			wait, err := guard.Wait(r.Context(), req.Email)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Error checking failed logins", "error", err)
+			} else if wait > 0 {
+				tooManyRequests(w, r, wait, "Too many failed logins for this account, try again later")
+				return
+			}
+		}
+
+		// Call the Login function
+		_, err = h.models.Login(r.Context(), w, req.Email, req.Password)
+		if err != nil {
+			if guard != nil && errors.Is(err, services.ErrUnauthorized) {
+				if _, err := guard.Failed(r.Context(), req.Email); err != nil {
+					logging.FromContext(r.Context()).Error("Error recording failed login", "error", err)
+				}
+			}
+			writeError(w, r, err)
+			return
+		}
+
+		if guard != nil {
+			if err := guard.Succeeded(r.Context(), req.Email); err != nil {
+				logging.FromContext(r.Context()).Error("Error clearing failed logins", "error", err)
+			}
+		}
 	}
 }
 
-func sendErrorResponse(w http.ResponseWriter, message string, code int) {
+// writeResponse sends a standard Response with its code as the HTTP status
+func writeResponse(w http.ResponseWriter, res Response) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+	w.WriteHeader(res.Code)
+	json.NewEncoder(w).Encode(res)
 }
 
-func GetUserByIDHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) GetUserByIDHandler(w http.ResponseWriter, r *http.Request) {
 	ids := r.URL.Query()["id"]
 	if len(ids) == 0 {
-		sendErrorResponse(w, "Missing 'id' query parameter", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "Missing 'id' query parameter")
 		return
 	}
-	users, err := services.GetUsersByID(ids)
+	users, err := h.models.GetUsersByID(r.Context(), ids)
 	if err != nil {
-		sendErrorResponse(w, err.Error(), http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,500 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/volunteerService-backend/services"
+)
+
+func TestHealthCheck(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.get("/api/v1/healthcheck")
+	expectStatus(t, rec, http.StatusOK)
+	if res := decode[Response](t, rec); res.Msg != "Health Check" {
+		t.Errorf("got msg %q", res.Msg)
+	}
+}
+
+func TestSignup(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       interface{}
+		wantStatus int
+		wantFields []string
+	}{
+		{"volunteer", volunteerFixture(), http.StatusOK, nil},
+		{"organisation", organisationFixture(), http.StatusOK, nil},
+		{"malformed body", "{", http.StatusBadRequest, nil},
+		{"empty", services.User{}, http.StatusBadRequest, []string{"email", "password", "contactNo", "userType"}},
+		{"invalid fields", func() services.User {
+			u := volunteerFixture()
+			u.Email = "not-an-email"
+			u.Password = "weak"
+			u.ContactNumber = "07911 000002"
+			u.VolunteerType = "astronaut"
+			return u
+		}(), http.StatusBadRequest, []string{"email", "password", "contactNo", "volType"}},
+		// 38 characters, but 73 bytes
+		{"password over bcrypt's limit", func() services.User {
+			u := volunteerFixture()
+			u.Password = "Aa1" + strings.Repeat("é", 35)
+			return u
+		}(), http.StatusBadRequest, []string{"password"}},
+		{"multi-byte password within bcrypt's limit", func() services.User {
+			u := volunteerFixture()
+			u.Password = "Aa1" + strings.Repeat("é", 34) + "x"
+			return u
+		}(), http.StatusOK, nil},
+		{"organisation without name", func() services.User {
+			u := organisationFixture()
+			u.OrganisationName = ""
+			return u
+		}(), http.StatusBadRequest, []string{"orgName"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+
+			rec := s.do(request{method: http.MethodPost, path: "/api/v1/signup", body: tt.body})
+			if tt.wantStatus == http.StatusOK {
+				expectStatus(t, rec, http.StatusOK)
+				return
+			}
+
+			p := expectProblem(t, rec, tt.wantStatus)
+			for _, field := range tt.wantFields {
+				if !hasFieldError(p, field) {
+					t.Errorf("missing error for %s in %+v", field, p.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestSignupConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(u *services.User)
+	}{
+		{"same email", func(u *services.User) { u.ContactNumber = "+447911999999" }},
+		{"same contact number", func(u *services.User) { u.Email = "other@example.com" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.signup(volunteerFixture())
+
+			user := volunteerFixture()
+			tt.modify(&user)
+			rec := s.do(request{method: http.MethodPost, path: "/api/v1/signup", body: user})
+			expectProblem(t, rec, http.StatusConflict)
+		})
+	}
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       interface{}
+		wantStatus int
+	}{
+		{"valid credentials", map[string]string{"email": "ada@example.com", "password": testPassword}, http.StatusOK},
+		{"wrong password", map[string]string{"email": "ada@example.com", "password": "Wr0ngPassword"}, http.StatusUnauthorized},
+		{"unknown email", map[string]string{"email": "nobody@example.com", "password": testPassword}, http.StatusUnauthorized},
+		{"malformed body", "{", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.signup(volunteerFixture())
+
+			rec := s.do(request{method: http.MethodPost, path: "/api/v1/login", body: tt.body})
+			if tt.wantStatus != http.StatusOK {
+				expectProblem(t, rec, tt.wantStatus)
+				if len(rec.Result().Cookies()) != 0 {
+					t.Error("failed login set a cookie")
+				}
+				return
+			}
+
+			expectStatus(t, rec, http.StatusOK)
+			res := decode[map[string]string](t, rec)
+			if res["email"] != "ada@example.com" || res["userType"] != "volunteer" || res["ID"] == "" {
+				t.Errorf("unexpected login response %v", res)
+			}
+
+			var cookie *http.Cookie
+			for _, c := range rec.Result().Cookies() {
+				if c.Name == "auth_token" {
+					cookie = c
+				}
+			}
+			if cookie == nil || cookie.Value == "" || !cookie.HttpOnly {
+				t.Fatalf("got auth cookie %+v, want a non-empty HttpOnly token", cookie)
+			}
+			if email, err := s.models.ParseJWT(cookie.Value); err != nil || email != "ada@example.com" {
+				t.Errorf("cookie token is for %q (%v)", email, err)
+			}
+		})
+	}
+}
+
+func TestLoginDeactivated(t *testing.T) {
+	s := newTestServer(t)
+
+	// Signing up can't grant a role
+	user := volunteerFixture()
+	user.Role = services.RoleAdmin
+	s.signup(user)
+	if stored, _ := s.models.GetUserByEmail(context.Background(), user.Email); stored.IsAdmin() {
+		t.Error("signup granted the admin role")
+	}
+
+	if _, err := s.models.SetDeactivated(context.Background(), user.Email, true); err != nil {
+		t.Fatal(err)
+	}
+	login := request{method: http.MethodPost, path: "/api/v1/login", body: LoginRequest{Email: user.Email, Password: testPassword}}
+	expectProblem(t, s.do(login), http.StatusForbidden)
+
+	s.models.SetDeactivated(context.Background(), user.Email, false)
+	expectStatus(t, s.do(login), http.StatusOK)
+}
+
+func TestDeactivatedToken(t *testing.T) {
+	s := newTestServer(t)
+	_, cookie := s.signupAndLogin(volunteerFixture())
+	me := request{method: http.MethodGet, path: "/api/v2/users/me", cookies: []*http.Cookie{cookie}}
+	expectStatus(t, s.do(me), http.StatusOK)
+
+	// The token issued before deactivation no longer authenticates
+	if _, err := s.models.SetDeactivated(context.Background(), volunteerFixture().Email, true); err != nil {
+		t.Fatal(err)
+	}
+	expectProblem(t, s.do(me), http.StatusUnauthorized)
+
+	s.models.SetDeactivated(context.Background(), volunteerFixture().Email, false)
+	expectStatus(t, s.do(me), http.StatusOK)
+}
+
+func TestGetTodos(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.get("/api/v1/todos")
+	expectStatus(t, rec, http.StatusOK)
+	if todos := decode[[]services.Todo](t, rec); len(todos) != 0 {
+		t.Fatalf("got %d todos from an empty store", len(todos))
+	}
+
+	s.createTodo(todoFixture(), nil)
+	s.createTodo(services.Todo{Task: "Walk dogs"}, nil)
+
+	rec = s.get("/api/v1/todos")
+	expectStatus(t, rec, http.StatusOK)
+	if todos := decode[[]services.Todo](t, rec); len(todos) != 2 {
+		t.Fatalf("got %d todos, want 2", len(todos))
+	}
+}
+
+func TestGetTodoById(t *testing.T) {
+	s := newTestServer(t)
+	id := s.createTodo(todoFixture(), nil)
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"existing", id, http.StatusOK},
+		{"unknown", "000000000000000000000000", http.StatusNotFound},
+		{"malformed", "not-an-id", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.get("/api/v1/todos/" + tt.id)
+			if tt.wantStatus != http.StatusOK {
+				expectProblem(t, rec, tt.wantStatus)
+				return
+			}
+
+			expectStatus(t, rec, http.StatusOK)
+			todo := decode[services.Todo](t, rec)
+			if todo.ID != id || todo.Task != todoFixture().Task {
+				t.Errorf("got %+v", todo)
+			}
+		})
+	}
+}
+
+func TestCreateTodo(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       interface{}
+		wantStatus int
+		wantFields []string
+	}{
+		{"valid", todoFixture(), http.StatusOK, nil},
+		{"malformed body", "{", http.StatusBadRequest, nil},
+		{"missing task", services.Todo{Description: "no task"}, http.StatusBadRequest, []string{"task"}},
+		{"unknown volType", func() services.Todo {
+			todo := todoFixture()
+			todo.VolunteerType = "astronaut"
+			return todo
+		}(), http.StatusBadRequest, []string{"volType"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+
+			rec := s.do(request{method: http.MethodPost, path: "/api/v1/todos/create", body: tt.body})
+			if tt.wantStatus != http.StatusOK {
+				p := expectProblem(t, rec, tt.wantStatus)
+				for _, field := range tt.wantFields {
+					if !hasFieldError(p, field) {
+						t.Errorf("missing error for %s in %+v", field, p.Errors)
+					}
+				}
+				return
+			}
+
+			expectStatus(t, rec, http.StatusOK)
+			res := decode[Response](t, rec)
+			if res.ID == "" {
+				t.Fatal("missing todo ID")
+			}
+			expectStatus(t, s.get("/api/v1/todos/"+res.ID), http.StatusOK)
+		})
+	}
+}
+
+func TestCreateTodoOrganisationReference(t *testing.T) {
+	s := newTestServer(t)
+	orgID, orgCookie := s.signupAndLogin(organisationFixture())
+	other := organisationFixture()
+	other.Email = "food@example.com"
+	other.OrganisationName = "Food Bank"
+	other.ContactNumber = "+447911000003"
+	otherID, _ := s.signupAndLogin(other)
+
+	// A client can't file a todo under another organisation by sending its ID
+	todo := todoFixture()
+	todo.OrganisationID = otherID
+	for _, cookie := range []*http.Cookie{orgCookie, nil} {
+		stored := decode[services.Todo](t, s.get("/api/v1/todos/"+s.createTodo(todo, cookie)))
+		if stored.OrganisationID != orgID {
+			t.Errorf("got orgId %q, want %q from the organisation name", stored.OrganisationID, orgID)
+		}
+	}
+
+	todo.OrganisationName = "Nobody"
+	stored := decode[services.Todo](t, s.get("/api/v1/todos/"+s.createTodo(todo, nil)))
+	if stored.OrganisationID != "" {
+		t.Errorf("got orgId %q for an unknown organisation", stored.OrganisationID)
+	}
+}
+
+func TestUpdateTodo(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         func(existing string) string
+		body       interface{}
+		wantStatus int
+	}{
+		{"complete", func(id string) string { return id }, services.Todo{Task: "Sort donations", Completed: true}, http.StatusOK},
+		{"add volunteer", func(id string) string { return id }, services.Todo{
+			Task:      "Sort donations",
+			Volunteer: []services.Volunteer{{VolunteerName: "Ada Lovelace"}},
+		}, http.StatusOK},
+		{"unknown", func(string) string { return "000000000000000000000000" }, services.Todo{Task: "x"}, http.StatusNotFound},
+		{"malformed body", func(id string) string { return id }, "{", http.StatusBadRequest},
+		{"missing task", func(id string) string { return id }, services.Todo{Completed: true}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			id := s.createTodo(todoFixture(), nil)
+
+			rec := s.do(request{method: http.MethodPut, path: "/api/v1/todos/update/" + tt.id(id), body: tt.body})
+			if tt.wantStatus != http.StatusOK {
+				expectProblem(t, rec, tt.wantStatus)
+				return
+			}
+			expectStatus(t, rec, http.StatusOK)
+
+			updated := decode[services.Todo](t, s.get("/api/v1/todos/"+id))
+			want := tt.body.(services.Todo)
+			if updated.Completed != want.Completed || len(updated.Volunteer) != len(want.Volunteer) {
+				t.Errorf("got %+v after update with %+v", updated, want)
+			}
+		})
+	}
+}
+
+func TestDeleteTodo(t *testing.T) {
+	s := newTestServer(t)
+	id := s.createTodo(todoFixture(), nil)
+
+	rec := s.do(request{method: http.MethodDelete, path: "/api/v1/todos/delete/" + id})
+	expectStatus(t, rec, http.StatusOK)
+	expectProblem(t, s.get("/api/v1/todos/"+id), http.StatusNotFound)
+
+	rec = s.do(request{method: http.MethodDelete, path: "/api/v1/todos/delete/" + id})
+	expectProblem(t, rec, http.StatusNotFound)
+}
+
+func TestTodoFilters(t *testing.T) {
+	s := newTestServer(t)
+	s.signup(organisationFixture())
+
+	s.createTodo(todoFixture(), nil)
+	s.createTodo(services.Todo{Task: "Plant trees", OrganisationName: "Green Earth", VolunteerType: "environment"}, nil)
+	s.createTodo(services.Todo{Task: "Tutor maths", OrganisationName: "Helping Hands", VolunteerType: "education"}, nil)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantTasks  []string
+	}{
+		{"by organisation", "/api/v1/todos/org?orgName=Helping+Hands", http.StatusOK, []string{"Sort donations", "Tutor maths"}},
+		{"by other organisation", "/api/v1/todos/org?orgName=Green+Earth", http.StatusOK, []string{"Plant trees"}},
+		{"by unknown organisation", "/api/v1/todos/org?orgName=Nobody", http.StatusOK, nil},
+		{"missing orgName", "/api/v1/todos/org", http.StatusBadRequest, nil},
+		{"by volunteer type", "/api/v1/todos/vol?volType=environment", http.StatusOK, []string{"Plant trees"}},
+		{"by unused volunteer type", "/api/v1/todos/vol?volType=technology", http.StatusOK, nil},
+		{"missing volType", "/api/v1/todos/vol", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.get(tt.path)
+			if tt.wantStatus != http.StatusOK {
+				expectProblem(t, rec, tt.wantStatus)
+				return
+			}
+
+			expectStatus(t, rec, http.StatusOK)
+			todos := decode[[]services.Todo](t, rec)
+			var tasks []string
+			for _, todo := range todos {
+				tasks = append(tasks, todo.Task)
+			}
+			if fmt.Sprint(tasks) != fmt.Sprint(tt.wantTasks) {
+				t.Errorf("got tasks %v, want %v", tasks, tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestGetUsersByID(t *testing.T) {
+	s := newTestServer(t)
+	adaID, _ := s.signupAndLogin(volunteerFixture())
+	orgID, _ := s.signupAndLogin(organisationFixture())
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantEmails []string
+	}{
+		{"one user", "?id=" + adaID, http.StatusOK, []string{"ada@example.com"}},
+		{"several users", "?id=" + adaID + "&id=" + orgID, http.StatusOK, []string{"ada@example.com", "org@example.com"}},
+		{"unknown user", "?id=000000000000000000000000", http.StatusOK, nil},
+		{"missing id", "", http.StatusBadRequest, nil},
+		{"malformed id", "?id=nope", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.get("/api/v1/users" + tt.query)
+			if tt.wantStatus != http.StatusOK {
+				expectProblem(t, rec, tt.wantStatus)
+				return
+			}
+
+			expectStatus(t, rec, http.StatusOK)
+			users := decode[[]services.User](t, rec)
+			var emails []string
+			for _, user := range users {
+				emails = append(emails, user.Email)
+			}
+			if fmt.Sprint(emails) != fmt.Sprint(tt.wantEmails) {
+				t.Errorf("got users %v, want %v", emails, tt.wantEmails)
+			}
+		})
+	}
+}
+
+// TestConcurrentCreateAndUpdate runs creates and updates in parallel; with `go test -race` it
+// also proves no request state is shared between handlers
+func TestConcurrentCreateAndUpdate(t *testing.T) {
+	s := newTestServer(t)
+	baseID := s.createTodo(services.Todo{Task: "base"}, nil)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan string, 2*n)
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			entry := services.Todo{Task: fmt.Sprintf("task-%d", i), Description: fmt.Sprintf("description-%d", i)}
+			if rec := s.do(request{method: http.MethodPost, path: "/api/v1/todos/create", body: entry}); rec.Code != http.StatusOK {
+				errs <- fmt.Sprintf("create %d: got %d: %s", i, rec.Code, rec.Body)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			entry := services.Todo{
+				Task:      "base",
+				Volunteer: []services.Volunteer{{VolunteerName: fmt.Sprintf("volunteer-%d", i)}},
+			}
+			if rec := s.do(request{method: http.MethodPut, path: "/api/v1/todos/update/" + baseID, body: entry}); rec.Code != http.StatusOK {
+				errs <- fmt.Sprintf("update %d: got %d: %s", i, rec.Code, rec.Body)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	todos := decode[[]services.Todo](t, s.get("/api/v1/todos"))
+	if len(todos) != n+1 {
+		t.Fatalf("got %d todos, want %d", len(todos), n+1)
+	}
+
+	for _, todo := range todos {
+		if todo.ID == baseID {
+			if len(todo.Volunteer) != n {
+				t.Errorf("base todo has %d volunteers, want %d", len(todo.Volunteer), n)
+			}
+			continue
+		}
+
+		// Fields must never leak from one request into another
+		var i int
+		if _, err := fmt.Sscanf(todo.Task, "task-%d", &i); err != nil {
+			t.Errorf("unexpected task %q", todo.Task)
+			continue
+		}
+		if want := fmt.Sprintf("description-%d", i); todo.Description != want {
+			t.Errorf("todo %q has description %q, want %q", todo.Task, todo.Description, want)
+		}
+		if len(todo.Volunteer) != 0 {
+			t.Errorf("todo %q has volunteers %v, want none", todo.Task, todo.Volunteer)
+		}
+	}
+}
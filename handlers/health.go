@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/volunteerService-backend/worker"
+)
+
+// healthCheckTimeout bounds how long each dependency check may take
+const healthCheckTimeout = 2 * time.Second
+
+// ComponentHealth is the result of checking a single dependency
+type ComponentHealth struct {
+	Name      string            `json:"name"`
+	Status    string            `json:"status"`
+	LatencyMs float64           `json:"latencyMs"`
+	Error     string            `json:"error,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// HealthReport is the detailed health of the API and its dependencies
+type HealthReport struct {
+	Status     string            `json:"status"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// Readiness is the result of the readiness probe, naming the failing components if any
+type Readiness struct {
+	Status  string   `json:"status"`
+	Failing []string `json:"failing,omitempty"`
+}
+
+// checkComponent times check and turns its result into a ComponentHealth
+func checkComponent(ctx context.Context, name string, check func(ctx context.Context) (map[string]string, error)) ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := check(ctx)
+	component := ComponentHealth{
+		Name:      name,
+		Status:    "up",
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   details,
+	}
+	if err != nil {
+		component.Status = "down"
+		component.Error = err.Error()
+	}
+
+	return component
+}
+
+// runHealthChecks checks Mongo connectivity, that the schema is migrated and the required indexes
+// exist, when backed by Mongo, and the background workers
+func (h *Handlers) runHealthChecks(ctx context.Context) HealthReport {
+	var components []ComponentHealth
+	if h.models.UsesMongo() {
+		components = append(components,
+			checkComponent(ctx, "mongo", func(ctx context.Context) (map[string]string, error) {
+				return nil, h.models.PingMongo(ctx)
+			}),
+			checkComponent(ctx, "migrations", func(ctx context.Context) (map[string]string, error) {
+				pending, err := h.models.PendingMigrations(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if len(pending) > 0 {
+					return nil, fmt.Errorf("pending migrations: %v", pending)
+				}
+				return nil, nil
+			}),
+			checkComponent(ctx, "indexes", func(ctx context.Context) (map[string]string, error) {
+				missing, err := h.models.MissingIndexes(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if len(missing) > 0 {
+					return nil, fmt.Errorf("missing indexes: %s", strings.Join(missing, ", "))
+				}
+				return nil, nil
+			}),
+		)
+	}
+	components = append(components,
+		checkComponent(ctx, "workers", func(ctx context.Context) (map[string]string, error) {
+			if h.workers == nil {
+				return nil, nil
+			}
+			statuses := h.workers.Status()
+			for name, status := range statuses {
+				if status != worker.StatusRunning {
+					return statuses, fmt.Errorf("worker %s is %s", name, status)
+				}
+			}
+			return statuses, nil
+		}),
+	)
+
+	report := HealthReport{
+		Status:     "up",
+		Timestamp:  time.Now(),
+		Components: components,
+	}
+	for _, component := range components {
+		if component.Status != "up" {
+			report.Status = "down"
+		}
+	}
+
+	return report
+}
+
+// livez reports the process is alive, without checking any dependency
+func livez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// readyz reports whether the API can serve traffic, with 503 when any dependency is down
+func (h *Handlers) readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.runHealthChecks(r.Context())
+
+	res := Readiness{Status: "ready"}
+
+	code := http.StatusOK
+	if report.Status != "up" {
+		code = http.StatusServiceUnavailable
+		res.Status = "not ready"
+		for _, component := range report.Components {
+			if component.Status != "up" {
+				res.Failing = append(res.Failing, component.Name)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
+
+// healthReport returns every component's status and latency for operators
+func (h *Handlers) healthReport(w http.ResponseWriter, r *http.Request) {
+	report := h.runHealthChecks(r.Context())
+
+	code := http.StatusOK
+	if report.Status != "up" {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantBody string
+	}{
+		{"liveness", "/livez", `"status":"ok"`},
+		{"readiness", "/readyz", `"status":"ready"`},
+		{"detailed health", "/api/v1/health", `"status":"up"`},
+		{"metrics", "/metrics", "volunteer_service_http_requests_total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.get(tt.path)
+			expectStatus(t, rec, http.StatusOK)
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body %q does not contain %q", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/ratelimit"
+	"github.com/volunteerService-backend/services"
+)
+
+// testJWTSecret signs the tokens issued during tests
+const testJWTSecret = "test-secret-that-is-at-least-32-characters"
+
+// testServer is the API booted over empty in-memory repositories
+type testServer struct {
+	t      *testing.T
+	router http.Handler
+	models *services.Models
+}
+
+// newTestServer boots the full router, middleware included, over a fresh in-memory store
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Storage.Backend = config.StorageMemory
+	cfg.Security.JWTSecret = testJWTSecret
+
+	models := services.New(nil, cfg)
+	return &testServer{
+		t:      t,
+		router: CreateRouter(cfg.Server, New(models, nil, ratelimit.NewMemoryStore())),
+		models: models,
+	}
+}
+
+// request describes one call to the API. Body is sent as JSON unless it is a string,
+// which is sent as is so malformed payloads can be tested. Like the frontend, the CSRF token
+// of an auth cookie is sent along unless noCSRF is set.
+type request struct {
+	method  string
+	path    string
+	body    interface{}
+	cookies []*http.Cookie
+	noCSRF  bool
+}
+
+// do sends req through the router and returns the recorded response
+func (s *testServer) do(req request) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	var body io.Reader = http.NoBody
+	switch b := req.body.(type) {
+	case nil:
+	case string:
+		body = strings.NewReader(b)
+	default:
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(b); err != nil {
+			s.t.Fatalf("encoding request body: %v", err)
+		}
+		body = &buf
+	}
+
+	r := httptest.NewRequest(req.method, req.path, body)
+	r.Header.Set("Content-Type", "application/json")
+	for _, cookie := range req.cookies {
+		r.AddCookie(cookie)
+		if cookie.Name == services.AuthCookie && !req.noCSRF {
+			r.Header.Set(services.CSRFHeader, s.models.CSRFToken(cookie.Value))
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, r)
+	return rec
+}
+
+// get is a shortcut for an unauthenticated GET
+func (s *testServer) get(path string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	return s.do(request{method: http.MethodGet, path: path})
+}
+
+// expectStatus fails the test when rec doesn't have the wanted status
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rec.Code != want {
+		t.Fatalf("got status %d, want %d: %s", rec.Code, want, rec.Body)
+	}
+}
+
+// decode unmarshals the JSON body of rec
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decoding response %q: %v", rec.Body, err)
+	}
+	return v
+}
+
+// expectProblem checks rec is a problem+json response with the wanted status,
+// and returns it so field errors can be inspected
+func expectProblem(t *testing.T, rec *httptest.ResponseRecorder, want int) Problem {
+	t.Helper()
+
+	expectStatus(t, rec, want)
+	if ct := rec.Header().Get("Content-Type"); ct != problemContentType {
+		t.Fatalf("got Content-Type %q, want %q", ct, problemContentType)
+	}
+	return decode[Problem](t, rec)
+}
+
+// hasFieldError reports whether p has an error for field
+func hasFieldError(p Problem, field string) bool {
+	for _, fe := range p.Errors {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Fixtures
+
+// testPassword satisfies the password strength rules
+const testPassword = "Sup3rSecret"
+
+// organisationFixture returns a valid organisation signup
+func organisationFixture() services.User {
+	return services.User{
+		Email:            "org@example.com",
+		Password:         testPassword,
+		ContactNumber:    "+447911000001",
+		UserType:         "organisation",
+		OrganisationName: "Helping Hands",
+		OrganisationType: "charity",
+	}
+}
+
+// volunteerFixture returns a valid volunteer signup
+func volunteerFixture() services.User {
+	return services.User{
+		FirstName:     "Ada",
+		LastName:      "Lovelace",
+		Email:         "ada@example.com",
+		Password:      testPassword,
+		ContactNumber: "+447911000002",
+		UserType:      "volunteer",
+		VolunteerType: "general",
+	}
+}
+
+// todoFixture returns a valid todo belonging to the organisation fixture
+func todoFixture() services.Todo {
+	return services.Todo{
+		Task:             "Sort donations",
+		Description:      "Sort the week's clothing donations",
+		OrganisationName: "Helping Hands",
+		VolunteerType:    "general",
+		OrganisationType: "charity",
+		VolunteersNeeded: 3,
+	}
+}
+
+// signup registers user, failing the test if it is rejected
+func (s *testServer) signup(user services.User) {
+	s.t.Helper()
+	rec := s.do(request{method: http.MethodPost, path: "/api/v1/signup", body: user})
+	expectStatus(s.t, rec, http.StatusOK)
+}
+
+// login signs user in and returns the auth cookie
+func (s *testServer) login(user services.User) *http.Cookie {
+	s.t.Helper()
+
+	rec := s.do(request{
+		method: http.MethodPost,
+		path:   "/api/v1/login",
+		body:   map[string]string{"email": user.Email, "password": user.Password},
+	})
+	expectStatus(s.t, rec, http.StatusOK)
+
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == "auth_token" {
+			return cookie
+		}
+	}
+	s.t.Fatal("login did not set the auth_token cookie")
+	return nil
+}
+
+// signupAndLogin registers user and returns its ID and auth cookie
+func (s *testServer) signupAndLogin(user services.User) (string, *http.Cookie) {
+	s.t.Helper()
+
+	s.signup(user)
+	cookie := s.login(user)
+
+	found, err := s.models.GetUserByEmail(context.Background(), user.Email)
+	if err != nil {
+		s.t.Fatalf("looking up %s: %v", user.Email, err)
+	}
+	return found.ID, cookie
+}
+
+// createTodo stores todo as the user of cookie, which may be nil, and returns its ID
+func (s *testServer) createTodo(todo services.Todo, cookie *http.Cookie) string {
+	s.t.Helper()
+
+	req := request{method: http.MethodPost, path: "/api/v1/todos/create", body: todo}
+	if cookie != nil {
+		req.cookies = []*http.Cookie{cookie}
+	}
+	rec := s.do(req)
+	expectStatus(s.t, rec, http.StatusOK)
+
+	res := decode[Response](s.t, rec)
+	if res.ID == "" {
+		s.t.Fatal("create did not return the todo ID")
+	}
+	return res.ID
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/volunteerService-backend/services"
+)
+
+// HistoryPage is a paginated slice of a todo's history
+type HistoryPage struct {
+	Entries []services.HistoryEntry `json:"entries"`
+	Page    int                     `json:"page"`
+	Limit   int                     `json:"limit"`
+	Total   int64                   `json:"total"`
+}
+
+// History pages hold defaultHistoryLimit entries unless asked otherwise, and at most maxHistoryLimit
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// historyPaging turns the page and limit asked for into the ones served, over REST and GraphQL alike.
+// Pages past services.MaxHistoryPage are left for GetTodoHistory to reject.
+func historyPaging(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultHistoryLimit
+	}
+	return page, min(limit, maxHistoryLimit)
+}
+
+// queryInt reads a positive integer query parameter, falling back to def when missing or invalid
+func queryInt(r *http.Request, name string, def int) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || value < 1 {
+		return def
+	}
+	return value
+}
+
+// getTodoHistory returns the change history of a todo to members of the owning organisation
+func (h *Handlers) getTodoHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	// Checked against the history itself, so it can still be read once the todo is deleted
+	if err := h.models.AuthorizeHistory(r.Context(), currentUserEmail(r), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, limit := historyPaging(queryInt(r, "page", 1), queryInt(r, "limit", defaultHistoryLimit))
+
+	entries, total, err := h.models.GetTodoHistory(r.Context(), id, page, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(HistoryPage{
+		Entries: entries,
+		Page:    page,
+		Limit:   limit,
+		Total:   total,
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/volunteerService-backend/services"
+)
+
+func TestTodoHistory(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	_, volunteerCookie := s.signupAndLogin(volunteerFixture())
+
+	id := s.createTodo(todoFixture(), orgCookie)
+	expectStatus(t, s.do(request{
+		method:  http.MethodPut,
+		path:    "/api/v1/todos/update/" + id,
+		body:    services.Todo{Task: "Sort donations", Completed: true},
+		cookies: []*http.Cookie{orgCookie},
+	}), http.StatusOK)
+
+	tests := []struct {
+		name       string
+		path       string
+		cookie     *http.Cookie
+		wantStatus int
+	}{
+		{"owner", "/api/v1/todos/" + id + "/history", orgCookie, http.StatusOK},
+		{"anonymous", "/api/v1/todos/" + id + "/history", nil, http.StatusUnauthorized},
+		{"other user", "/api/v1/todos/" + id + "/history", volunteerCookie, http.StatusForbidden},
+		{"unknown todo", "/api/v1/todos/000000000000000000000000/history", orgCookie, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request{method: http.MethodGet, path: tt.path}
+			if tt.cookie != nil {
+				req.cookies = []*http.Cookie{tt.cookie}
+			}
+			rec := s.do(req)
+			if tt.wantStatus != http.StatusOK {
+				expectProblem(t, rec, tt.wantStatus)
+				return
+			}
+
+			expectStatus(t, rec, http.StatusOK)
+			page := decode[HistoryPage](t, rec)
+			if page.Total != 2 || page.Entries[0].Action != services.HistoryUpdate || page.Entries[1].Action != services.HistoryCreate {
+				t.Errorf("got history %+v", page)
+			}
+			if page.Entries[0].Actor != "org@example.com" {
+				t.Errorf("got actor %q", page.Entries[0].Actor)
+			}
+		})
+	}
+}
+
+func TestDeletedTodoHistory(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	_, volunteerCookie := s.signupAndLogin(volunteerFixture())
+
+	id := s.createTodo(todoFixture(), orgCookie)
+	expectStatus(t, s.do(request{method: http.MethodDelete, path: "/api/v1/todos/delete/" + id, cookies: []*http.Cookie{orgCookie}}), http.StatusOK)
+
+	rec := s.do(request{method: http.MethodGet, path: "/api/v1/todos/" + id + "/history", cookies: []*http.Cookie{orgCookie}})
+	expectStatus(t, rec, http.StatusOK)
+	page := decode[HistoryPage](t, rec)
+	if page.Total != 2 || page.Entries[0].Action != services.HistoryDelete {
+		t.Errorf("got history %+v", page)
+	}
+
+	rec = s.do(request{method: http.MethodGet, path: "/api/v1/todos/" + id + "/history", cookies: []*http.Cookie{volunteerCookie}})
+	expectProblem(t, rec, http.StatusForbidden)
+}
+
+func TestTodoHistoryPagination(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+
+	id := s.createTodo(todoFixture(), orgCookie)
+	for _, task := range []string{"Sort toys", "Sort books"} {
+		expectStatus(t, s.do(request{
+			method:  http.MethodPut,
+			path:    "/api/v1/todos/update/" + id,
+			body:    services.Todo{Task: task},
+			cookies: []*http.Cookie{orgCookie},
+		}), http.StatusOK)
+	}
+
+	tests := []struct {
+		query       string
+		wantStatus  int
+		wantEntries int
+	}{
+		{"?limit=2", http.StatusOK, 2},
+		{"?limit=2&page=2", http.StatusOK, 1},
+		{"?limit=2&page=3", http.StatusOK, 0},
+		{"?limit=100&page=10000", http.StatusOK, 0},
+		{"?page=0", http.StatusOK, 3}, // Falls back to the first page
+		{"?page=10001", http.StatusBadRequest, 0},
+		{"?limit=100&page=9223372036854775807", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := s.do(request{method: http.MethodGet, path: "/api/v1/todos/" + id + "/history" + tt.query, cookies: []*http.Cookie{orgCookie}})
+			if tt.wantStatus != http.StatusOK {
+				if p := expectProblem(t, rec, tt.wantStatus); !hasFieldError(p, "page") {
+					t.Errorf("missing error for page in %+v", p.Errors)
+				}
+				return
+			}
+
+			expectStatus(t, rec, http.StatusOK)
+			if page := decode[HistoryPage](t, rec); len(page.Entries) != tt.wantEntries || page.Total != 3 {
+				t.Errorf("got %d of %d entries, want %d of 3", len(page.Entries), page.Total, tt.wantEntries)
+			}
+		})
+	}
+}
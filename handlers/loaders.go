@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/volunteerService-backend/services"
+)
+
+// batchLoader collects the keys requested while a GraphQL query resolves one level and fetches
+// them all with a single call when the first result is needed, avoiding a query per parent object.
+// Results are cached for the rest of the request.
+type batchLoader[T any] struct {
+	fetch func(ctx context.Context, keys []string) (map[string]T, error)
+
+	mu      sync.Mutex
+	pending []string
+	queued  map[string]bool
+	results map[string]T
+	errs    map[string]error
+}
+
+func newBatchLoader[T any](fetch func(ctx context.Context, keys []string) (map[string]T, error)) *batchLoader[T] {
+	return &batchLoader[T]{
+		fetch:   fetch,
+		queued:  map[string]bool{},
+		results: map[string]T{},
+		errs:    map[string]error{},
+	}
+}
+
+// load queues key and returns a thunk, which graphql-go calls once every field of the
+// current level has been resolved. The thunk returns nil when nothing exists for key.
+func (l *batchLoader[T]) load(ctx context.Context, key string) func() (interface{}, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+
+			results, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					l.errs[k] = err
+				} else if result, ok := results[k]; ok {
+					l.results[k] = result
+				}
+			}
+		}
+
+		if err := l.errs[key]; err != nil {
+			return nil, err
+		}
+		if result, ok := l.results[key]; ok {
+			return result, nil
+		}
+		return nil, nil
+	}
+}
+
+// loaders are the batch loaders of one GraphQL request
+type loaders struct {
+	users *batchLoader[services.User]
+}
+
+const loadersKey contextKey = "loaders"
+
+// withLoaders returns ctx carrying fresh loaders over models for a GraphQL request
+func withLoaders(ctx context.Context, models *services.Models) context.Context {
+	return context.WithValue(ctx, loadersKey, &loaders{
+		users: newBatchLoader(models.LoadUsers),
+	})
+}
+
+// loadersFrom returns the loaders of the request ctx belongs to
+func loadersFrom(ctx context.Context) *loaders {
+	l, _ := ctx.Value(loadersKey).(*loaders)
+	return l
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/services"
+)
+
+// requestIDHeader carries the ID used to correlate all the logs of one request
+const requestIDHeader = "X-Request-ID"
+
+type contextKey string
+
+const userEmailKey contextKey = "userEmail"
+
+// loggedUserKey holds a *string that authenticate fills with the caller's email, so
+// requestLogger, which runs before it, can log who made the request
+const loggedUserKey contextKey = "loggedUser"
+
+// authenticate reads the JWT from the 'auth_token' cookie or a Bearer Authorization header.
+// Requests without a valid token, or with the token of a deactivated user, are let through
+// anonymously; use requireAuth to reject them.
+func (h *Handlers) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := ""
+		if cookie, err := r.Cookie(services.AuthCookie); err == nil {
+			token = cookie.Value
+		}
+		if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+			token = strings.TrimPrefix(header, "Bearer ")
+		}
+
+		if token != "" {
+			email, err := h.models.Authenticate(r.Context(), token)
+			switch {
+			case err == nil:
+				r = r.WithContext(context.WithValue(r.Context(), userEmailKey, email))
+				if user, ok := r.Context().Value(loggedUserKey).(*string); ok {
+					*user = email
+				}
+			case !errors.Is(err, services.ErrUnauthorized):
+				writeError(w, r, err)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// csrfProtect rejects state-changing requests authenticated by the auth cookie unless they carry
+// the session's CSRF token in the X-CSRF-Token header. Other sites can make the browser send the
+// cookie but can't read it to derive the token. Requests with a Bearer token are exempt,
+// since browsers never attach one by themselves.
+func (h *Handlers) csrfProtect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cookie, err := r.Cookie(services.AuthCookie)
+		if err != nil || currentUserEmail(r) == "" || strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		want := h.models.CSRFToken(cookie.Value)
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(services.CSRFHeader)), []byte(want)) != 1 {
+			writeProblem(w, r, http.StatusForbidden, "Missing or invalid CSRF token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAuth rejects requests that were not authenticated
+func requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if currentUserEmail(r) == "" {
+			writeProblem(w, r, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// currentUserEmail returns the email of the authenticated user, or "" for anonymous requests
+func currentUserEmail(r *http.Request) string {
+	return currentUserEmailFrom(r.Context())
+}
+
+// currentUserEmailFrom returns the email of the user authenticated for the request of ctx
+func currentUserEmailFrom(ctx context.Context) string {
+	email, _ := ctx.Value(userEmailKey).(string)
+	return email
+}
+
+// deprecated marks every response as coming from an API version deprecated since the given
+// time and removed at sunset, linking to its successor (RFC 9745 and RFC 8594)
+func deprecated(since, sunset time.Time, successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(since.Unix(), 10))
+			w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			w.Header().Add("Link", "<"+successor+">; rel=\"successor-version\"")
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestLogger assigns a request ID, or propagates the caller's X-Request-ID, attaches a
+// logger carrying it to the request context and logs every request once it completes
+func requestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = logging.NewRequestID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		logger := slog.Default().With("requestId", requestID)
+		user := new(string)
+		ctx := logging.WithLogger(r.Context(), logger)
+		r = r.WithContext(context.WithValue(ctx, loggedUserKey, user))
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := r.URL.Path
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		logger.Info("request",
+			"method", r.Method,
+			"route", route,
+			"status", status,
+			"latencyMs", float64(time.Since(start).Microseconds())/1000,
+			"user", *user,
+		)
+	})
+}
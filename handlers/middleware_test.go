@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/volunteerService-backend/services"
+)
+
+// captureLogs sends the default logger to a buffer for the rest of the test and returns
+// a function decoding the JSON lines logged so far
+func captureLogs(t *testing.T) func() []map[string]interface{} {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	return func() []map[string]interface{} {
+		var lines []map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+		for dec.More() {
+			var line map[string]interface{}
+			if err := dec.Decode(&line); err != nil {
+				t.Fatal(err)
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+}
+
+// findLog returns the first line logged with msg, or nil
+func findLog(lines []map[string]interface{}, msg string) map[string]interface{} {
+	for _, line := range lines {
+		if line["msg"] == msg {
+			return line
+		}
+	}
+	return nil
+}
+
+func TestRequestLogUser(t *testing.T) {
+	s := newTestServer(t)
+	_, cookie := s.signupAndLogin(organisationFixture())
+	logs := captureLogs(t)
+
+	rec := s.do(request{method: http.MethodGet, path: "/api/v1/todos", cookies: []*http.Cookie{cookie}})
+	expectStatus(t, rec, http.StatusOK)
+
+	// The logger runs before authentication, yet still logs who made the request
+	line := findLog(logs(), "request")
+	if line == nil {
+		t.Fatal("got no request log")
+	}
+	if line["user"] != organisationFixture().Email || line["requestId"] != rec.Header().Get(requestIDHeader) {
+		t.Errorf("got log %v", line)
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	router := newStubRouter(func(ctx context.Context) ([]services.Todo, error) {
+		panic("boom")
+	})
+	logs := captureLogs(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil))
+	expectProblem(t, rec, http.StatusInternalServerError)
+
+	requestID := rec.Header().Get(requestIDHeader)
+	if requestID == "" {
+		t.Fatal("got no request ID on the recovered response")
+	}
+	recovered := findLog(logs(), "Recovered from panic")
+	if recovered == nil || recovered["requestId"] != requestID {
+		t.Errorf("got panic log %v, want it tagged with request %s", recovered, requestID)
+	}
+	if line := findLog(logs(), "request"); line == nil || line["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("got request log %v, want the 500 logged", line)
+	}
+}
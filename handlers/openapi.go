@@ -0,0 +1,487 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volunteerService-backend/services"
+)
+
+// Schema is a JSON Schema as used by OpenAPI 3.1
+type Schema map[string]interface{}
+
+// OpenAPI is the root of an OpenAPI 3.1 document
+type OpenAPI struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       OpenAPIInfo                      `json:"info"`
+	Servers    []OpenAPIServer                  `json:"servers"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components OpenAPIComponents                `json:"components"`
+}
+
+// OpenAPIInfo describes the API
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIServer is a base URL the API is served from
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+// OpenAPIComponents holds the reusable schemas and security schemes
+type OpenAPIComponents struct {
+	Schemas         map[string]Schema `json:"schemas"`
+	SecuritySchemes map[string]Schema `json:"securitySchemes"`
+}
+
+// Operation is a single method on a path
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*APIResult `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+	Schema      Schema `json:"schema"`
+}
+
+// RequestBody is the JSON body an operation accepts
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// APIResult is one of the responses an operation can return
+type APIResult struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body in one content type
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// apiParam documents a query parameter; path parameters are taken from the route pattern
+type apiParam struct {
+	name        string
+	description string
+	required    bool
+	schema      Schema
+}
+
+// apiRoute documents one route registered in CreateRouter. Request and response bodies are
+// given as values of the Go types the handlers decode and encode, so the schemas can't drift.
+type apiRoute struct {
+	method     string
+	path       string
+	tag        string
+	summary    string
+	query      []apiParam
+	body       interface{}
+	response   interface{}
+	status     int    // success status, 200 when zero
+	errors     []int  // problem+json statuses the route can return
+	auth       bool   // requires the auth_token cookie or a Bearer token
+	csv        bool   // also available as text/csv with format=csv
+	plainText  bool   // response is not JSON, e.g. the docs UI and Prometheus metrics
+	download   string // media type of a file response, e.g. a backup archive
+	deprecated bool   // superseded by v2, responses carry Deprecation and Sunset headers
+
+	errorBody interface{} // body of the error statuses when they aren't problem+json
+}
+
+var (
+	stringSchema = Schema{"type": "string"}
+	dateSchema   = Schema{"type": "string", "format": "date", "description": "YYYY-MM-DD or RFC 3339"}
+)
+
+// analyticsQuery are the query parameters shared by every analytics route
+var analyticsQuery = []apiParam{
+	{name: "orgName", description: "Organisation to report on", required: true, schema: stringSchema},
+	{name: "from", description: "Only todos starting on or after this date", schema: dateSchema},
+	{name: "to", description: "Only todos starting before this date", schema: dateSchema},
+	{name: "format", description: "Respond with CSV instead of JSON", schema: Schema{"type": "string", "enum": []string{"json", "csv"}}},
+}
+
+// apiRoutes lists every route served by CreateRouter
+var apiRoutes = []apiRoute{
+	{method: "GET", path: "/livez", tag: "health", summary: "Liveness probe", response: map[string]string{}},
+	{method: "GET", path: "/readyz", tag: "health", summary: "Readiness probe, 503 while a dependency is down", response: Readiness{}, errors: []int{503}},
+	{method: "GET", path: "/metrics", tag: "health", summary: "Prometheus metrics", plainText: true},
+	{method: "POST", path: "/graphql", tag: "graphql", summary: "Run a GraphQL query, limited in depth and complexity", body: GraphQLRequest{}, response: GraphQLResponse{}, errors: []int{400}, errorBody: GraphQLResponse{}},
+	{method: "GET", path: "/api/v1/healthcheck", tag: "health", summary: "Simple health check", response: Response{}},
+	{method: "GET", path: "/api/v1/health", tag: "health", summary: "Detailed health of every dependency", response: HealthReport{}},
+	{method: "GET", path: "/api/v1/openapi.json", tag: "docs", summary: "This OpenAPI document", response: map[string]interface{}{}},
+	{method: "GET", path: "/api/v1/docs", tag: "docs", summary: "Interactive API documentation", plainText: true},
+
+	{method: "GET", path: "/api/v1/todos", deprecated: true, tag: "todos", summary: "List all todos", response: []services.Todo{}, errors: []int{404},
+		query: []apiParam{{name: "availableFor", description: "Only todos fitting this user's availability", schema: stringSchema}}},
+	{method: "GET", path: "/api/v1/todos/{id}", deprecated: true, tag: "todos", summary: "Get a todo", response: services.Todo{}, errors: []int{404}},
+	{method: "GET", path: "/api/v1/todos/org", deprecated: true, tag: "todos", summary: "List the todos of an organisation", response: []services.Todo{}, errors: []int{400, 404},
+		query: []apiParam{
+			{name: "orgName", description: "Organisation name", required: true, schema: stringSchema},
+			{name: "availableFor", description: "Only todos fitting this user's availability", schema: stringSchema},
+		}},
+	{method: "GET", path: "/api/v1/todos/vol", deprecated: true, tag: "todos", summary: "List the todos for a volunteer type", response: []services.Todo{}, errors: []int{400, 404},
+		query: []apiParam{
+			{name: "volType", description: "Volunteer type", required: true, schema: Schema{"type": "string", "enum": services.VolunteerTypes}},
+			{name: "availableFor", description: "Only todos fitting this user's availability", schema: stringSchema},
+		}},
+	{method: "POST", path: "/api/v1/todos/create", deprecated: true, tag: "todos", summary: "Create a todo", body: services.Todo{}, response: Response{}, errors: []int{400}},
+	{method: "PUT", path: "/api/v1/todos/update/{id}", deprecated: true, tag: "todos", summary: "Set the task and completion of a todo and add volunteers",
+		body: services.Todo{}, response: Response{}, errors: []int{400, 404, 409},
+		query: []apiParam{{name: "onConflict", description: "'warn' to accept volunteers with schedule conflicts, returning warnings", schema: Schema{"type": "string", "enum": []string{"warn"}}}}},
+	{method: "DELETE", path: "/api/v1/todos/delete/{id}", deprecated: true, tag: "todos", summary: "Delete a todo", response: Response{}, errors: []int{404}},
+	{method: "POST", path: "/api/v1/todos/{id}/clone", deprecated: true, tag: "todos", summary: "Copy a todo into a new open todo", response: Response{}, status: 201, errors: []int{404}},
+	{method: "GET", path: "/api/v1/todos/{id}/history", deprecated: true, tag: "todos", summary: "Change history of a todo, for its organisation", response: HistoryPage{}, errors: []int{400, 401, 403, 404}, auth: true,
+		query: []apiParam{
+			{name: "page", schema: Schema{"type": "integer", "minimum": 1, "maximum": services.MaxHistoryPage, "default": 1}},
+			{name: "limit", schema: Schema{"type": "integer", "minimum": 1, "maximum": 100, "default": 20}},
+		}},
+
+	{method: "POST", path: "/api/v1/signup", deprecated: true, tag: "users", summary: "Register a volunteer or organisation", body: services.User{}, response: SignupResponse{}, errors: []int{400, 409}},
+	{method: "POST", path: "/api/v1/login", deprecated: true, tag: "users", summary: "Log in, setting the auth_token cookie", body: LoginRequest{}, response: services.LoginResponse{}, errors: []int{400, 401}},
+	{method: "GET", path: "/api/v1/users", deprecated: true, tag: "users", summary: "Look up users by ID", response: []services.User{}, errors: []int{400},
+		query: []apiParam{{name: "id", description: "User ID, repeat for several users", required: true, schema: Schema{"type": "array", "items": stringSchema}}}},
+	{method: "GET", path: "/api/v1/users/{id}/availability", deprecated: true, tag: "users", summary: "Get a user's availability", response: services.Availability{}, errors: []int{404}},
+	{method: "PUT", path: "/api/v1/users/{id}/availability", deprecated: true, tag: "users", summary: "Replace your own availability", body: services.Availability{}, response: Response{}, errors: []int{400, 401, 403}, auth: true},
+
+	{method: "GET", path: "/api/v1/templates", deprecated: true, tag: "templates", summary: "List the templates of your organisation", response: []services.Template{}, errors: []int{400, 401, 403}, auth: true,
+		query: []apiParam{{name: "orgName", description: "Organisation name", required: true, schema: stringSchema}}},
+	{method: "GET", path: "/api/v1/templates/{id}", deprecated: true, tag: "templates", summary: "Get a template", response: services.Template{}, errors: []int{401, 403, 404}, auth: true},
+	{method: "POST", path: "/api/v1/templates/create", deprecated: true, tag: "templates", summary: "Create a template for your organisation", body: services.Template{}, response: Response{}, status: 201, errors: []int{400, 401, 403}, auth: true},
+	{method: "PUT", path: "/api/v1/templates/update/{id}", deprecated: true, tag: "templates", summary: "Change the defaults set in the body, keeping the rest of the template", body: services.Template{}, response: Response{}, errors: []int{400, 401, 403, 404}, auth: true},
+	{method: "DELETE", path: "/api/v1/templates/delete/{id}", deprecated: true, tag: "templates", summary: "Delete a template", response: Response{}, errors: []int{401, 403, 404}, auth: true},
+	{method: "POST", path: "/api/v1/templates/{id}/todos", deprecated: true, tag: "templates", summary: "Create a todo from a template, with the body as overrides", body: services.Todo{}, response: Response{}, status: 201, errors: []int{400, 401, 403, 404}, auth: true},
+
+	{method: "GET", path: "/api/v1/analytics/org/status", deprecated: true, tag: "analytics", summary: "Open and completed todos over time", response: []services.StatusBucket{}, errors: []int{400, 401, 403}, csv: true, auth: true,
+		query: append([]apiParam{{name: "bucket", schema: Schema{"type": "string", "enum": []string{"day", "week", "month"}, "default": "day"}}}, analyticsQuery...)},
+	{method: "GET", path: "/api/v1/analytics/org/fill", deprecated: true, tag: "analytics", summary: "How well todos are staffed", response: services.FillStats{}, errors: []int{400, 401, 403}, csv: true, auth: true, query: analyticsQuery},
+	{method: "GET", path: "/api/v1/analytics/org/voltypes", deprecated: true, tag: "analytics", summary: "Volunteers per volunteer type", response: []services.VolTypeCount{}, errors: []int{400, 401, 403}, csv: true, auth: true, query: analyticsQuery},
+	{method: "GET", path: "/api/v1/analytics/org/repeat", deprecated: true, tag: "analytics", summary: "Volunteers coming back for more than one todo", response: services.RepeatStats{}, errors: []int{400, 401, 403}, csv: true, auth: true, query: analyticsQuery},
+
+	{method: "GET", path: "/api/v2/todos", tag: "v2", summary: "List todos", response: Envelope[[]services.Todo]{}, errors: []int{400},
+		query: []apiParam{
+			{name: "orgName", description: "Only todos of this organisation", schema: stringSchema},
+			{name: "volType", description: "Only todos for this volunteer type", schema: Schema{"type": "string", "enum": services.VolunteerTypes}},
+			{name: "availableFor", description: "Only todos fitting this user's availability", schema: stringSchema},
+		}},
+	{method: "POST", path: "/api/v2/todos", tag: "v2", summary: "Create a todo", body: services.Todo{}, response: Envelope[services.Todo]{}, status: 201, errors: []int{400}},
+	{method: "GET", path: "/api/v2/todos/{id}", tag: "v2", summary: "Get a todo", response: Envelope[services.Todo]{}, errors: []int{404}},
+	{method: "PATCH", path: "/api/v2/todos/{id}", tag: "v2", summary: "Change the task or completion of a todo", body: TodoPatch{}, response: Envelope[services.Todo]{}, errors: []int{400, 404}},
+	{method: "DELETE", path: "/api/v2/todos/{id}", tag: "v2", summary: "Delete a todo", status: 204, errors: []int{404}},
+	{method: "GET", path: "/api/v2/todos/{id}/volunteers", tag: "v2", summary: "List the volunteers of a todo", response: Envelope[[]services.Volunteer]{}, errors: []int{404}},
+	{method: "POST", path: "/api/v2/todos/{id}/volunteers", tag: "v2", summary: "Sign a volunteer up for a todo, as that volunteer or the owning organisation", body: services.Volunteer{}, response: Envelope[services.Volunteer]{}, status: 201, errors: []int{400, 401, 403, 404, 409}, auth: true,
+		query: []apiParam{{name: "onConflict", description: "'warn' to accept schedule conflicts, returning warnings", schema: Schema{"type": "string", "enum": []string{"warn"}}}}},
+	{method: "DELETE", path: "/api/v2/todos/{id}/volunteers/{volunteerId}", tag: "v2", summary: "Take a volunteer off a todo, as that volunteer or the owning organisation", status: 204, errors: []int{401, 403, 404}, auth: true},
+	{method: "GET", path: "/api/v2/organisations", tag: "v2", summary: "List organisations", response: Envelope[[]services.Organisation]{}},
+	{method: "GET", path: "/api/v2/organisations/{id}", tag: "v2", summary: "Get an organisation", response: Envelope[services.Organisation]{}, errors: []int{404}},
+	{method: "GET", path: "/api/v2/organisations/{id}/todos", tag: "v2", summary: "List the todos of an organisation", response: Envelope[[]services.Todo]{}, errors: []int{404}},
+	{method: "GET", path: "/api/v2/users/me", tag: "v2", summary: "Profile of the authenticated user", response: Envelope[services.User]{}, errors: []int{401, 404}, auth: true},
+	{method: "GET", path: "/api/v2/admin/backup", tag: "admin", summary: "Download a backup archive of every collection", download: "application/zip", errors: []int{401, 403, 501}, auth: true},
+}
+
+// openAPISpec builds the document once, on first use
+var openAPISpec = sync.OnceValue(func() OpenAPI {
+	return buildOpenAPI(apiRoutes)
+})
+
+// pathParamPattern matches the {name} parameters of a chi route pattern
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+func buildOpenAPI(routes []apiRoute) OpenAPI {
+	g := schemaGenerator{schemas: map[string]Schema{}}
+	g.schemas["Problem"] = g.schemaFor(reflect.TypeOf(Problem{}))
+
+	doc := OpenAPI{
+		OpenAPI: "3.1.0",
+		Info: OpenAPIInfo{
+			Title:   "Volunteer Service API",
+			Version: "1.0.0",
+			Description: "Errors are returned as RFC 7807 application/problem+json. " +
+				"Requests other than GET, HEAD and OPTIONS authenticated by the auth_token cookie must send the csrf_token cookie's value in the X-CSRF-Token header.",
+		},
+		Servers: []OpenAPIServer{{URL: "/"}},
+		Paths:   map[string]map[string]*Operation{},
+		Components: OpenAPIComponents{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]Schema{
+				"cookieAuth": {"type": "apiKey", "in": "cookie", "name": services.AuthCookie},
+				"bearerAuth": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+
+	for _, route := range routes {
+		op := &Operation{
+			Tags:        []string{route.tag},
+			Summary:     route.summary,
+			OperationID: operationID(route.method, route.path),
+			Responses:   map[string]*APIResult{},
+		}
+
+		for _, match := range pathParamPattern.FindAllStringSubmatch(route.path, -1) {
+			op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: stringSchema})
+		}
+		for _, param := range route.query {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:        param.name,
+				In:          "query",
+				Description: param.description,
+				Required:    param.required,
+				Schema:      param.schema,
+			})
+		}
+
+		if route.body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: g.schemaFor(reflect.TypeOf(route.body))}},
+			}
+		}
+
+		status := route.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		result := &APIResult{Description: http.StatusText(status)}
+		switch {
+		case route.plainText:
+			result.Content = map[string]MediaType{"text/plain": {Schema: stringSchema}}
+		case route.download != "":
+			result.Content = map[string]MediaType{route.download: {Schema: Schema{"type": "string", "format": "binary"}}}
+		case route.response != nil:
+			result.Content = map[string]MediaType{"application/json": {Schema: g.schemaFor(reflect.TypeOf(route.response))}}
+			if route.csv {
+				result.Content["text/csv"] = MediaType{Schema: stringSchema}
+			}
+		}
+		op.Responses[strconv.Itoa(status)] = result
+
+		errorContent := map[string]MediaType{problemContentType: {Schema: Schema{"$ref": "#/components/schemas/Problem"}}}
+		if route.errorBody != nil {
+			errorContent = map[string]MediaType{"application/json": {Schema: g.schemaFor(reflect.TypeOf(route.errorBody))}}
+		}
+		for _, code := range append(route.errors, http.StatusInternalServerError) {
+			op.Responses[strconv.Itoa(code)] = &APIResult{Description: http.StatusText(code), Content: errorContent}
+		}
+		// Cookie-authenticated writes without the CSRF token are refused before reaching the route
+		if route.method != http.MethodGet && op.Responses["403"] == nil {
+			op.Responses["403"] = &APIResult{
+				Description: http.StatusText(http.StatusForbidden),
+				Content:     map[string]MediaType{problemContentType: {Schema: Schema{"$ref": "#/components/schemas/Problem"}}},
+			}
+		}
+
+		// Any route reading or writing the database may time out
+		if strings.HasPrefix(route.path, "/api/") && route.tag != "docs" && route.tag != "health" {
+			op.Responses["504"] = &APIResult{
+				Description: http.StatusText(http.StatusGatewayTimeout),
+				Content:     map[string]MediaType{problemContentType: {Schema: Schema{"$ref": "#/components/schemas/Problem"}}},
+			}
+		}
+
+		// The rate limit is checked before the route, so it's always a problem
+		if route.path == "/graphql" || strings.HasPrefix(route.path, "/api/") {
+			op.Responses["429"] = &APIResult{
+				Description: http.StatusText(http.StatusTooManyRequests),
+				Content:     map[string]MediaType{problemContentType: {Schema: Schema{"$ref": "#/components/schemas/Problem"}}},
+			}
+		}
+
+		if route.auth {
+			op.Security = []map[string][]string{{"cookieAuth": {}}, {"bearerAuth": {}}}
+		}
+		op.Deprecated = route.deprecated
+
+		if doc.Paths[route.path] == nil {
+			doc.Paths[route.path] = map[string]*Operation{}
+		}
+		doc.Paths[route.path][strings.ToLower(route.method)] = op
+	}
+
+	return doc
+}
+
+// operationID derives a stable identifier such as "get_api_v1_todos_id" from a route
+func operationID(method, path string) string {
+	id := strings.ToLower(method) + strings.NewReplacer("/", "_", "{", "", "}", "", ".", "_").Replace(path)
+	return strings.Trim(id, "_")
+}
+
+// schemaGenerator derives JSON Schemas from Go types using their json and validate tags.
+// Named structs are added to schemas once and referenced everywhere else.
+type schemaGenerator struct {
+	schemas map[string]Schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) Schema {
+	if t == timeType {
+		return Schema{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaFor(t.Elem())
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = nil // placeholder so recursive types terminate
+			g.schemas[name] = g.structSchema(t)
+		}
+		return Schema{"$ref": "#/components/schemas/" + name}
+	}
+
+	return Schema{}
+}
+
+// schemaName names the component of a struct after its Go type, e.g. services.Todo becomes "Todo".
+// Instances of generic types are named after their argument, e.g. Envelope[[]services.Todo] becomes "TodoListEnvelope".
+func schemaName(t reflect.Type) string {
+	name, arg, generic := strings.Cut(t.Name(), "[")
+	if !generic {
+		return name
+	}
+
+	arg = strings.TrimSuffix(arg, "]")
+	list := strings.HasPrefix(arg, "[]")
+	arg = arg[strings.LastIndex(arg, ".")+1:]
+	if list {
+		arg += "List"
+	}
+	return arg + name
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) Schema {
+	properties := map[string]interface{}{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := g.schemaFor(field.Type)
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		for _, rule := range rules {
+			if rule == "dive" {
+				break // the remaining rules apply to the elements
+			}
+			key, param, _ := strings.Cut(rule, "=")
+			switch key {
+			case "required":
+				required = append(required, name)
+			case "email":
+				schema["format"] = "email"
+			case "e164":
+				schema["pattern"] = `^\+[1-9]\d{1,14}$`
+			case "usertype":
+				schema["enum"] = services.UserTypes
+			case "voltype":
+				schema["enum"] = services.VolunteerTypes
+			case "orgtype":
+				schema["enum"] = services.OrganisationTypes
+			case "max", "min":
+				n, err := strconv.Atoi(param)
+				if err != nil {
+					continue
+				}
+				switch field.Type.Kind() {
+				case reflect.String:
+					schema[key+"Length"] = n
+				case reflect.Slice:
+					schema[key+"Items"] = n
+				default:
+					schema[map[string]string{"max": "maximum", "min": "minimum"}[key]] = n
+				}
+			}
+		}
+		properties[name] = schema
+	}
+
+	schema := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// getOpenAPI serves the OpenAPI document
+func getOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(openAPISpec())
+}
+
+// docsPage renders the OpenAPI document with Swagger UI
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Volunteer Service API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// getDocs serves the interactive API documentation
+func getDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(docsPage))
+}
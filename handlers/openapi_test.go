@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/ratelimit"
+)
+
+// TestOpenAPICoversRoutes fails when a route is registered in CreateRouter without a spec entry, or the other way round
+func TestOpenAPICoversRoutes(t *testing.T) {
+	router := CreateRouter(config.Default().Server, New(nil, nil, ratelimit.NewMemoryStore()))
+	spec := openAPISpec()
+
+	registered := map[string]bool{}
+	err := chi.Walk(router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		key := strings.ToLower(method) + " " + route
+		registered[key] = true
+
+		if spec.Paths[route][strings.ToLower(method)] == nil {
+			t.Errorf("%s %s has no OpenAPI entry", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if !registered[method+" "+path] {
+				t.Errorf("OpenAPI documents %s %s, which isn't routed", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.get("/api/v1/openapi.json")
+	expectStatus(t, rec, http.StatusOK)
+	doc := decode[OpenAPI](t, rec)
+
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("got openapi %q", doc.OpenAPI)
+	}
+	for _, name := range []string{"Todo", "User", "Response", "Problem"} {
+		if doc.Components.Schemas[name] == nil {
+			t.Errorf("missing %s schema", name)
+		}
+	}
+
+	user := doc.Components.Schemas["User"]
+	required, _ := user["required"].([]interface{})
+	if !containsValue(required, "email") {
+		t.Errorf("User schema doesn't require email: %v", user["required"])
+	}
+
+	rec = s.get("/api/v1/docs")
+	expectStatus(t, rec, http.StatusOK)
+	if !strings.Contains(rec.Body.String(), "/api/v1/openapi.json") {
+		t.Error("docs page doesn't load the spec")
+	}
+}
+
+// containsValue reports whether values holds want
+func containsValue(values []interface{}, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/services"
+)
+
+// problemContentType is the media type of RFC 7807 error responses
+const problemContentType = "application/problem+json"
+
+// statusClientClosedRequest is logged for requests the client gave up on, as nginx does
+const statusClientClosedRequest = 499
+
+// Problem is an RFC 7807 problem details response, sent for every failed request
+type Problem struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	RequestID string                `json:"requestId,omitempty"`
+	Errors    []services.FieldError `json:"errors,omitempty"`
+	Conflicts []string              `json:"conflicts,omitempty"`
+}
+
+// newProblem returns a Problem for status about the request r
+func newProblem(r *http.Request, status int, detail string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+}
+
+// sendProblem writes p as application/problem+json with its status
+func sendProblem(w http.ResponseWriter, p Problem) {
+	p.RequestID = w.Header().Get(requestIDHeader)
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// writeProblem sends a problem response with the given status and detail
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	sendProblem(w, newProblem(r, status, detail))
+}
+
+// writeError maps an error returned by the services to a problem response. Domain errors
+// keep their message; anything else is logged and reported as a generic 500.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrValidation):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, services.ErrUnsupported):
+		status = http.StatusNotImplemented
+	case services.IsCanceled(err):
+		// No one is left to read a response
+		logging.FromContext(r.Context()).Info("Request cancelled", "error", err)
+		w.WriteHeader(statusClientClosedRequest)
+		return
+	case services.IsTimeout(err):
+		logging.FromContext(r.Context()).Warn("Database timeout", "error", err)
+		writeProblem(w, r, http.StatusGatewayTimeout, "The database took too long to respond")
+		return
+	}
+
+	if status == http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error("Internal error", "error", err)
+		writeProblem(w, r, status, "An unexpected error occurred")
+		return
+	}
+
+	p := newProblem(r, status, err.Error())
+	var domainErr *services.Error
+	if errors.As(err, &domainErr) {
+		p.Detail = domainErr.Msg
+		p.Errors = domainErr.Fields
+	}
+	sendProblem(w, p)
+}
+
+// recoverer turns a panic in a handler into a 500 problem response instead of a dropped connection
+func recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				logging.FromContext(r.Context()).Error("Recovered from panic", "panic", rec, "stack", string(debug.Stack()))
+				writeProblem(w, r, http.StatusInternalServerError, "An unexpected error occurred")
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
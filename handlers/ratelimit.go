@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/ratelimit"
+)
+
+// rateLimitHeaders are the response headers describing the limit, as in the IETF RateLimit header fields draft
+var rateLimitHeaders = []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"}
+
+// seconds rounds d up to whole seconds for a header
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// tooManyRequests rejects a request that may be retried after wait
+func tooManyRequests(w http.ResponseWriter, r *http.Request, wait time.Duration, detail string) {
+	w.Header().Set("Retry-After", seconds(wait))
+	writeProblem(w, r, http.StatusTooManyRequests, detail)
+}
+
+// clientIP is the address the request came from. X-Forwarded-For is deliberately not trusted,
+// since any client could set it to get a fresh bucket.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimitKey returns who the request is counted against for the given kind of key
+func rateLimitKey(r *http.Request, kind string) string {
+	switch kind {
+	case config.RateLimitByGroup:
+		return "group"
+	case config.RateLimitByUser:
+		if email := currentUserEmail(r); email != "" {
+			return "user:" + email
+		}
+	}
+	return "ip:" + clientIP(r)
+}
+
+// rateLimit gives the route group a token bucket for each key of limit, rejecting requests
+// with 429 once theirs is empty. When the counters can't be reached requests are let through.
+func rateLimit(cfg config.RateLimitConfig, store ratelimit.Store, group string, limit config.RateLimit) func(http.Handler) http.Handler {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	limiter := ratelimit.NewLimiter(store, group, limit)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := limiter.Allow(r.Context(), rateLimitKey(r, limit.Key))
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Error checking rate limit", "group", group, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(res.Reset))
+			w.Header().Set("RateLimit-Policy", limiter.Policy())
+
+			if !res.Allowed {
+				tooManyRequests(w, r, res.RetryAfter, "Rate limit exceeded, try again later")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// newLoginGuard returns the guard against repeated failed logins, or nil when rate limiting is off
+func newLoginGuard(cfg config.RateLimitConfig, store ratelimit.Store) *ratelimit.LoginGuard {
+	if !cfg.Enabled {
+		return nil
+	}
+	return ratelimit.NewLoginGuard(store, cfg.Login)
+}
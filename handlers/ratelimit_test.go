@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/volunteerService-backend/config"
+)
+
+func TestRateLimit(t *testing.T) {
+	s := newTestServer(t)
+	burst := config.Default().Server.RateLimit.Auth.Burst
+
+	// Malformed logins are cheap and still count against the auth limit
+	for i := 0; i < burst; i++ {
+		rec := s.do(request{method: http.MethodPost, path: "/api/v1/login", body: "{"})
+		expectProblem(t, rec, http.StatusBadRequest)
+		if got := rec.Header().Get("RateLimit-Remaining"); got != strconv.Itoa(burst-i-1) {
+			t.Fatalf("request %d got RateLimit-Remaining %q", i, got)
+		}
+	}
+
+	rec := s.do(request{method: http.MethodPost, path: "/api/v1/login", body: "{"})
+	expectProblem(t, rec, http.StatusTooManyRequests)
+	if rec.Header().Get("Retry-After") == "" || rec.Header().Get("RateLimit-Policy") == "" {
+		t.Errorf("got headers %v, want Retry-After and RateLimit-Policy", rec.Header())
+	}
+
+	// The other routes only count against the API limit
+	rec = s.get("/api/v2/todos")
+	expectStatus(t, rec, http.StatusOK)
+	if rec.Header().Get("RateLimit-Limit") != strconv.Itoa(config.Default().Server.RateLimit.API.Burst) {
+		t.Errorf("got RateLimit-Limit %q", rec.Header().Get("RateLimit-Limit"))
+	}
+
+	// Probes are never limited
+	if rec := s.get("/livez"); rec.Header().Get("RateLimit-Limit") != "" {
+		t.Error("got a rate limit on /livez")
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	s := newTestServer(t)
+	s.signup(volunteerFixture())
+
+	login := func(password string) *http.Response {
+		body := LoginRequest{Email: volunteerFixture().Email, Password: password}
+		return s.do(request{method: http.MethodPost, path: "/api/v1/login", body: body}).Result()
+	}
+
+	if res := login("Wr0ngPassword"); res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("got status %d for a wrong password", res.StatusCode)
+	}
+
+	// Until the delay has passed even the right password is refused
+	res := login(testPassword)
+	if res.StatusCode != http.StatusTooManyRequests || res.Header.Get("Retry-After") != "1" {
+		t.Errorf("got status %d, Retry-After %q, want 429 after 1s", res.StatusCode, res.Header.Get("Retry-After"))
+	}
+
+	// Other accounts are unaffected
+	s.signup(organisationFixture())
+	s.login(organisationFixture())
+}
@@ -1,45 +1,120 @@
 package handlers
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/metrics"
+	"github.com/volunteerService-backend/services"
 )
 
-func CreateRouter() *chi.Mux {
+// v1 of the API is deprecated in favour of v2 and is removed at v1Sunset
+var (
+	v1Deprecated = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	v1Sunset     = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
 
+// CreateRouter builds the API router serving h, using the server configuration
+func CreateRouter(cfg config.ServerConfig, h *Handlers) *chi.Mux {
 	router := chi.NewRouter()
 
+	// Logging and recovery come first, so panics anywhere in the chain are logged and recovered
+	router.Use(requestLogger)
+	router.Use(recoverer)
+	router.Use(metrics.Middleware)
+	router.Use(h.authenticate)
+
 	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTION"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CRSF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", services.CSRFHeader, requestIDHeader},
+		ExposedHeaders:   append([]string{"Link", "Location", "Deprecation", "Sunset", services.CSRFHeader, requestIDHeader}, rateLimitHeaders...),
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
+	router.Use(h.csrfProtect)
+
+	// Probes for the orchestrator
+	router.Get("/livez", livez)
+	router.Get("/readyz", h.readyz)
+	router.Method(http.MethodGet, "/metrics", metrics.Handler())
+
+	// Every API route shares one limit; login and signup have a stricter one on top
+	apiLimit := rateLimit(cfg.RateLimit, h.counters, "api", cfg.RateLimit.API)
+	authLimit := rateLimit(cfg.RateLimit, h.counters, "auth", cfg.RateLimit.Auth)
+
+	// GraphQL over the same services and authorization rules as REST
+	router.With(apiLimit).Method(http.MethodPost, "/graphql", newGraphQLEndpoint(h, cfg.GraphQL))
 
 	router.Route("/api", func(router chi.Router) {
+		router.Use(apiLimit)
 
 		// version 1
 		router.Route("/v1", func(router chi.Router) {
 
 			router.Get("/healthcheck", healthCheck)
-			router.Get("/todos", getTodos)
-			router.Get("/todos/{id}", getTodoById)
-			router.Get("/todos/org", getTodoByOrg) // Filter by Organisation Name
-			router.Get("/todos/vol", getTodoByVol) // Filter by Volunteer Type
-			router.Post("/todos/create", createTodo)
-			router.Put("/todos/update/{id}", updateTodo)
-			router.Delete("/todos/delete/{id}", deleteTodo)
-			router.Post("/signup", SignupHandler)
-			router.Post("/login", LoginHandler)
-			router.Get("/users", GetUserByIDHandler)
+			router.Get("/health", h.healthReport) // Detailed status of every dependency
+			router.Get("/openapi.json", getOpenAPI)
+			router.Get("/docs", getDocs) // Swagger UI over openapi.json
+
+			// The resource routes are superseded by v2
+			router.Group(func(router chi.Router) {
+				router.Use(deprecated(v1Deprecated, v1Sunset, "/api/v2"))
+
+				router.Get("/todos", h.getTodos)
+				router.Get("/todos/{id}", h.getTodoById)
+				router.Get("/todos/org", h.getTodoByOrg) // Filter by Organisation Name
+				router.Get("/todos/vol", h.getTodoByVol) // Filter by Volunteer Type
+				router.Post("/todos/create", h.createTodo)
+				router.Put("/todos/update/{id}", h.updateTodo)
+				router.Delete("/todos/delete/{id}", h.deleteTodo)
+				router.Post("/todos/{id}/clone", h.cloneTodo)
+				router.With(requireAuth).Get("/todos/{id}/history", h.getTodoHistory)
+				router.With(authLimit).Post("/signup", h.SignupHandler)
+				router.With(authLimit).Post("/login", h.LoginHandler(newLoginGuard(cfg.RateLimit, h.counters)))
+				router.Get("/users", h.GetUserByIDHandler)
+				router.Get("/users/{id}/availability", h.getAvailability)
+				router.With(requireAuth).Put("/users/{id}/availability", h.setAvailability)
+
+				// Organisation-scoped todo templates, for the members of that organisation only
+				router.With(requireAuth).Get("/templates", h.getTemplatesByOrg) // Filter by Organisation Name
+				router.With(requireAuth).Get("/templates/{id}", h.getTemplateById)
+				router.With(requireAuth).Post("/templates/create", h.createTemplate)
+				router.With(requireAuth).Put("/templates/update/{id}", h.updateTemplate)
+				router.With(requireAuth).Delete("/templates/delete/{id}", h.deleteTemplate)
+				router.With(requireAuth).Post("/templates/{id}/todos", h.createTodoFromTemplate)
+
+				// Organisation analytics, all filtered by 'orgName' with optional 'from', 'to' and 'format=csv',
+				// for the members of that organisation only
+				router.With(requireAuth).Get("/analytics/org/status", h.getOrgStatusAnalytics)
+				router.With(requireAuth).Get("/analytics/org/fill", h.getOrgFillAnalytics)
+				router.With(requireAuth).Get("/analytics/org/voltypes", h.getOrgVolTypeAnalytics)
+				router.With(requireAuth).Get("/analytics/org/repeat", h.getOrgRepeatAnalytics)
+			})
 
 		})
 
-		// version 2 - add it if you want
-		// router.Route("/v2", func(router chi.Router) {
-		// })
+		// version 2, RESTful resources with every response in an Envelope
+		router.Route("/v2", func(router chi.Router) {
+
+			router.Get("/todos", h.listTodosV2) // Filter by 'orgName' or 'volType', and 'availableFor'
+			router.Post("/todos", h.createTodoV2)
+			router.Get("/todos/{id}", h.getTodoV2)
+			router.Patch("/todos/{id}", h.patchTodoV2)
+			router.Delete("/todos/{id}", h.deleteTodoV2)
+			router.Get("/todos/{id}/volunteers", h.listTodoVolunteersV2)
+			router.With(requireAuth).Post("/todos/{id}/volunteers", h.addTodoVolunteerV2)
+			router.With(requireAuth).Delete("/todos/{id}/volunteers/{volunteerId}", h.removeTodoVolunteerV2)
+			router.Get("/organisations", h.listOrganisationsV2)
+			router.Get("/organisations/{id}", h.getOrganisationV2)
+			router.Get("/organisations/{id}/todos", h.listOrganisationTodosV2)
+			router.With(requireAuth).Get("/users/me", h.getCurrentUserV2)
+			router.With(requireAuth, h.requireAdmin).Get("/admin/backup", h.exportBackup) // Download a backup of every collection
+
+		})
 
 	})
 
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/volunteerService-backend/services"
+)
+
+func (h *Handlers) getTemplatesByOrg(w http.ResponseWriter, r *http.Request) {
+	orgName := r.URL.Query().Get("orgName")
+	if orgName == "" {
+		writeProblem(w, r, http.StatusBadRequest, "Missing 'orgName' query parameter")
+		return
+	}
+
+	// Names aren't unique, so the templates are those of the caller's organisation
+	organisation, err := h.models.AuthorizeOrganisation(r.Context(), currentUserEmail(r), orgName)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	templates, err := h.models.GetTemplatesByOrganisationID(r.Context(), organisation.ID, organisation.Name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(templates)
+}
+
+// authorizedTemplate loads the template named in the path and checks the caller belongs to its
+// organisation, writing the problem and returning false otherwise
+func (h *Handlers) authorizedTemplate(w http.ResponseWriter, r *http.Request) (services.Template, bool) {
+	template, err := h.models.GetTemplateById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return services.Template{}, false
+	}
+
+	if err := h.models.AuthorizeTemplate(r.Context(), currentUserEmail(r), template); err != nil {
+		writeError(w, r, err)
+		return services.Template{}, false
+	}
+
+	return template, true
+}
+
+func (h *Handlers) getTemplateById(w http.ResponseWriter, r *http.Request) {
+	template, ok := h.authorizedTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *Handlers) createTemplate(w http.ResponseWriter, r *http.Request) {
+	var template services.Template
+	err := json.NewDecoder(r.Body).Decode(&template)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if template.OrganisationName == "" {
+		writeError(w, r, services.Invalid(services.FieldError{Field: "orgName", Message: "is required"}))
+		return
+	}
+	if _, err := h.models.AuthorizeOrganisation(r.Context(), currentUserEmail(r), template.OrganisationName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := h.models.InsertTemplate(r.Context(), template, currentUserEmail(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeResponse(w, Response{
+		Msg:  "Successfully created template",
+		Code: 201,
+		ID:   id,
+	})
+}
+
+func (h *Handlers) updateTemplate(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.authorizedTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	var template services.Template
+	err := json.NewDecoder(r.Body).Decode(&template)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = h.models.UpdateTemplate(r.Context(), existing.ID, template)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeResponse(w, Response{
+		Msg:  "Successfully updated template",
+		Code: 200,
+	})
+}
+
+func (h *Handlers) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.authorizedTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.models.DeleteTemplate(r.Context(), existing.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeResponse(w, Response{
+		Msg:  "Successfully deleted template",
+		Code: 200,
+	})
+}
+
+// createTodoFromTemplate creates a todo from a template, with the request body as per-instance
+// overrides. An empty body, however it is framed, means no overrides.
+func (h *Handlers) createTodoFromTemplate(w http.ResponseWriter, r *http.Request) {
+	template, ok := h.authorizedTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	var overrides services.Todo
+	err := json.NewDecoder(r.Body).Decode(&overrides)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	todoID, err := h.models.CreateTodoFromTemplate(r.Context(), template.ID, overrides, currentUserEmail(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeResponse(w, Response{
+		Msg:  "Successfully created todo",
+		Code: 201,
+		ID:   todoID,
+	})
+}
+
+func (h *Handlers) cloneTodo(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	cloneID, err := h.todos.CloneTodo(r.Context(), id, currentUserEmail(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeResponse(w, Response{
+		Msg:  "Successfully cloned todo",
+		Code: 201,
+		ID:   cloneID,
+	})
+}
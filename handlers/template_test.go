@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/volunteerService-backend/services"
+)
+
+// templateFixture returns a valid template belonging to the organisation fixture
+func templateFixture() services.Template {
+	return services.Template{
+		Name:             "Weekly sort",
+		OrganisationName: "Helping Hands",
+		Task:             "Sort donations",
+		Description:      "Sort the week's clothing donations",
+		VolunteerType:    "general",
+		VolunteersNeeded: 4,
+	}
+}
+
+// createTemplate stores template as the user of cookie and returns its ID
+func (s *testServer) createTemplate(template services.Template, cookie *http.Cookie) string {
+	s.t.Helper()
+
+	rec := s.do(request{method: http.MethodPost, path: "/api/v1/templates/create", body: template, cookies: []*http.Cookie{cookie}})
+	expectStatus(s.t, rec, http.StatusCreated)
+	return decode[Response](s.t, rec).ID
+}
+
+func TestTemplateCRUD(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	org := []*http.Cookie{orgCookie}
+
+	expectProblem(t, s.do(request{method: http.MethodPost, path: "/api/v1/templates/create", body: services.Template{Name: "no org"}, cookies: org}), http.StatusBadRequest)
+	id := s.createTemplate(templateFixture(), orgCookie)
+
+	tests := []struct {
+		name       string
+		req        request
+		wantStatus int
+	}{
+		{"list by organisation", request{method: http.MethodGet, path: "/api/v1/templates?orgName=Helping+Hands", cookies: org}, http.StatusOK},
+		{"list without orgName", request{method: http.MethodGet, path: "/api/v1/templates", cookies: org}, http.StatusBadRequest},
+		{"get", request{method: http.MethodGet, path: "/api/v1/templates/" + id, cookies: org}, http.StatusOK},
+		{"get unknown", request{method: http.MethodGet, path: "/api/v1/templates/000000000000000000000000", cookies: org}, http.StatusNotFound},
+		{"update", request{method: http.MethodPut, path: "/api/v1/templates/update/" + id, body: services.Template{Name: "Renamed", Task: "Sort toys"}, cookies: org}, http.StatusOK},
+		{"update unknown", request{method: http.MethodPut, path: "/api/v1/templates/update/000000000000000000000000", body: templateFixture(), cookies: org}, http.StatusNotFound},
+		{"update malformed", request{method: http.MethodPut, path: "/api/v1/templates/update/" + id, body: "{", cookies: org}, http.StatusBadRequest},
+		{"delete unknown", request{method: http.MethodDelete, path: "/api/v1/templates/delete/000000000000000000000000", cookies: org}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(tt.req)
+			if tt.wantStatus != http.StatusOK {
+				expectProblem(t, rec, tt.wantStatus)
+				return
+			}
+			expectStatus(t, rec, http.StatusOK)
+		})
+	}
+
+	updated := decode[services.Template](t, s.do(request{method: http.MethodGet, path: "/api/v1/templates/" + id, cookies: org}))
+	if updated.Name != "Renamed" || updated.OrganisationName != "Helping Hands" {
+		t.Errorf("got %+v after update", updated)
+	}
+
+	expectStatus(t, s.do(request{method: http.MethodDelete, path: "/api/v1/templates/delete/" + id, cookies: org}), http.StatusOK)
+	expectProblem(t, s.do(request{method: http.MethodGet, path: "/api/v1/templates/" + id, cookies: org}), http.StatusNotFound)
+}
+
+func TestTemplateAuthorization(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	_, volunteerCookie := s.signupAndLogin(volunteerFixture())
+	id := s.createTemplate(templateFixture(), orgCookie)
+
+	other := organisationFixture()
+	other.Email = "food@example.com"
+	other.OrganisationName = "Food Bank"
+	other.ContactNumber = "+447911000003"
+	_, otherCookie := s.signupAndLogin(other)
+
+	requests := []request{
+		{method: http.MethodGet, path: "/api/v1/templates?orgName=Helping+Hands"},
+		{method: http.MethodGet, path: "/api/v1/templates/" + id},
+		{method: http.MethodPost, path: "/api/v1/templates/create", body: templateFixture()},
+		{method: http.MethodPut, path: "/api/v1/templates/update/" + id, body: services.Template{Name: "Hijacked"}},
+		{method: http.MethodDelete, path: "/api/v1/templates/delete/" + id},
+		{method: http.MethodPost, path: "/api/v1/templates/" + id + "/todos"},
+	}
+	callers := []struct {
+		name       string
+		cookie     *http.Cookie
+		wantStatus int
+	}{
+		{"anonymous", nil, http.StatusUnauthorized},
+		{"volunteer", volunteerCookie, http.StatusForbidden},
+		{"other organisation", otherCookie, http.StatusForbidden},
+	}
+
+	for _, caller := range callers {
+		for _, req := range requests {
+			t.Run(caller.name+" "+req.method+" "+req.path, func(t *testing.T) {
+				if caller.cookie != nil {
+					req.cookies = []*http.Cookie{caller.cookie}
+				}
+				expectProblem(t, s.do(req), caller.wantStatus)
+			})
+		}
+	}
+
+	template := decode[services.Template](t, s.do(request{method: http.MethodGet, path: "/api/v1/templates/" + id, cookies: []*http.Cookie{orgCookie}}))
+	if template.Name != "Weekly sort" {
+		t.Errorf("got %+v, want the template untouched", template)
+	}
+
+	// Another organisation taking the same name gets its own templates, not these
+	twin := organisationFixture()
+	twin.Email, twin.ContactNumber = "twin@example.com", "+447911000004"
+	_, twinCookie := s.signupAndLogin(twin)
+	rec := s.do(request{method: http.MethodGet, path: "/api/v1/templates?orgName=Helping+Hands", cookies: []*http.Cookie{twinCookie}})
+	expectStatus(t, rec, http.StatusOK)
+	if templates := decode[[]services.Template](t, rec); len(templates) != 0 {
+		t.Errorf("got templates %+v for an organisation sharing the name", templates)
+	}
+	expectProblem(t, s.do(request{method: http.MethodGet, path: "/api/v1/templates/" + id, cookies: []*http.Cookie{twinCookie}}), http.StatusForbidden)
+}
+
+func TestCreateTodoFromTemplate(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	id := s.createTemplate(templateFixture(), orgCookie)
+
+	rec := s.do(request{method: http.MethodPost, path: "/api/v1/templates/" + id + "/todos", body: services.Todo{VolunteersNeeded: 10}, cookies: []*http.Cookie{orgCookie}})
+	expectStatus(t, rec, http.StatusCreated)
+
+	todo := decode[services.Todo](t, s.get("/api/v1/todos/"+decode[Response](t, rec).ID))
+	if todo.Task != "Sort donations" || todo.OrganisationName != "Helping Hands" || todo.VolunteersNeeded != 10 {
+		t.Errorf("got %+v", todo)
+	}
+
+	rec = s.do(request{method: http.MethodPost, path: "/api/v1/templates/000000000000000000000000/todos", cookies: []*http.Cookie{orgCookie}})
+	expectProblem(t, rec, http.StatusNotFound)
+
+	// A chunked body has no Content-Length, empty or not
+	for _, body := range []string{"", `{"volNeeded": 7}`} {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/templates/"+id+"/todos", io.NopCloser(strings.NewReader(body)))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set(services.CSRFHeader, s.models.CSRFToken(orgCookie.Value))
+		r.AddCookie(orgCookie)
+		if r.ContentLength != -1 {
+			t.Fatalf("got Content-Length %d, want an unknown length", r.ContentLength)
+		}
+
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, r)
+		expectStatus(t, rec, http.StatusCreated)
+
+		todo := decode[services.Todo](t, s.get("/api/v1/todos/"+decode[Response](t, rec).ID))
+		want := 4
+		if body != "" {
+			want = 7
+		}
+		if todo.Task != "Sort donations" || todo.VolunteersNeeded != want {
+			t.Errorf("got %+v for chunked body %q", todo, body)
+		}
+	}
+}
+
+func TestCloneTodo(t *testing.T) {
+	s := newTestServer(t)
+	id := s.createTodo(todoFixture(), nil)
+	expectStatus(t, s.do(request{method: http.MethodPut, path: "/api/v1/todos/update/" + id, body: services.Todo{
+		Task:      "Sort donations",
+		Completed: true,
+		Volunteer: []services.Volunteer{{VolunteerName: "Ada Lovelace"}},
+	}}), http.StatusOK)
+
+	rec := s.do(request{method: http.MethodPost, path: "/api/v1/todos/" + id + "/clone"})
+	expectStatus(t, rec, http.StatusCreated)
+
+	clone := decode[services.Todo](t, s.get("/api/v1/todos/"+decode[Response](t, rec).ID))
+	if clone.Task != "Sort donations" || clone.Completed || len(clone.Volunteer) != 0 {
+		t.Errorf("got clone %+v, want an open copy without volunteers", clone)
+	}
+
+	expectProblem(t, s.do(request{method: http.MethodPost, path: "/api/v1/todos/000000000000000000000000/clone"}), http.StatusNotFound)
+}
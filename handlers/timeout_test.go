@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/ratelimit"
+	"github.com/volunteerService-backend/services"
+)
+
+// stubTodos serves every todo list with list, and panics on anything else
+type stubTodos struct {
+	TodoService
+	list func(ctx context.Context) ([]services.Todo, error)
+}
+
+func (s stubTodos) GetAllTodos(ctx context.Context) ([]services.Todo, error) {
+	return s.list(ctx)
+}
+
+func newStubRouter(list func(ctx context.Context) ([]services.Todo, error)) http.Handler {
+	cfg := config.Default()
+	cfg.Storage.Backend = config.StorageMemory
+
+	h := New(services.New(nil, cfg), nil, ratelimit.NewMemoryStore())
+	h.todos = stubTodos{list: list}
+	return CreateRouter(cfg.Server, h)
+}
+
+func TestDatabaseTimeout(t *testing.T) {
+	router := newStubRouter(func(ctx context.Context) ([]services.Todo, error) {
+		return nil, fmt.Errorf("finding todos: %w", context.DeadlineExceeded)
+	})
+
+	for _, path := range []string{"/api/v1/todos", "/api/v2/todos"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		expectProblem(t, rec, http.StatusGatewayTimeout)
+	}
+}
+
+func TestRequestCancellation(t *testing.T) {
+	started := make(chan struct{})
+	router := newStubRouter(func(ctx context.Context) ([]services.Todo, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/todos", nil).WithContext(ctx))
+		close(done)
+	}()
+
+	// The client going away must abort the work in progress
+	<-started
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request kept running after it was cancelled")
+	}
+
+	if rec.Code != statusClientClosedRequest {
+		t.Errorf("got status %d, want %d", rec.Code, statusClientClosedRequest)
+	}
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/volunteerService-backend/services"
+)
+
+// Envelope wraps the body of every successful v2 response. Errors are problem+json as in v1.
+type Envelope[T any] struct {
+	Data T     `json:"data"`
+	Meta *Meta `json:"meta,omitempty"`
+}
+
+// Meta carries information about the data of an Envelope
+type Meta struct {
+	Count    int      `json:"count"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// TodoPatch is a partial update of a todo; fields left out are not changed
+type TodoPatch struct {
+	Task      *string `json:"task,omitempty"`
+	Completed *bool   `json:"completed,omitempty"`
+}
+
+// writeData sends data wrapped in an Envelope with the given status
+func writeData[T any](w http.ResponseWriter, status int, data T, meta *Meta) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Envelope[T]{Data: data, Meta: meta})
+}
+
+// writeList sends a collection with its size in the Envelope's meta
+func writeList[T any](w http.ResponseWriter, items []T) {
+	writeData(w, http.StatusOK, items, &Meta{Count: len(items)})
+}
+
+// listTodosV2 lists todos, optionally filtered by 'orgName' or 'volType' and 'availableFor'
+func (h *Handlers) listTodosV2(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var todos []services.Todo
+	var err error
+	switch {
+	case query.Get("orgName") != "":
+		todos, err = h.todos.GetTodosByOrg(r.Context(), query.Get("orgName"))
+	case query.Get("volType") != "":
+		todos, err = h.todos.GetTodosByVolType(r.Context(), query.Get("volType"))
+	default:
+		todos, err = h.todos.GetAllTodos(r.Context())
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	todos, err = h.filterAvailableFor(r, todos)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeList(w, todos)
+}
+
+// createTodoV2 creates a todo and returns it with its location
+func (h *Handlers) createTodoV2(w http.ResponseWriter, r *http.Request) {
+	var entry services.Todo
+	err := json.NewDecoder(r.Body).Decode(&entry)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	id, err := h.todos.InsertTodo(r.Context(), entry, currentUserEmail(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.todos.GetTodoById(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/v2/todos/"+id)
+	writeData(w, http.StatusCreated, created, nil)
+}
+
+func (h *Handlers) getTodoV2(w http.ResponseWriter, r *http.Request) {
+	todo, err := h.todos.GetTodoById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, todo, nil)
+}
+
+// patchTodoV2 changes the task or completion of a todo and returns the result
+func (h *Handlers) patchTodoV2(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var patch TodoPatch
+	err := json.NewDecoder(r.Body).Decode(&patch)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	existing, err := h.todos.GetTodoById(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entry := services.Todo{Task: existing.Task, Completed: existing.Completed}
+	if patch.Task != nil {
+		entry.Task = *patch.Task
+	}
+	if patch.Completed != nil {
+		entry.Completed = *patch.Completed
+	}
+
+	err = h.todos.UpdateTodo(r.Context(), id, entry, currentUserEmail(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.getTodoV2(w, r)
+}
+
+func (h *Handlers) deleteTodoV2(w http.ResponseWriter, r *http.Request) {
+	err := h.todos.DeleteTodo(r.Context(), chi.URLParam(r, "id"), currentUserEmail(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) listTodoVolunteersV2(w http.ResponseWriter, r *http.Request) {
+	todo, err := h.todos.GetTodoById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	volunteers := todo.Volunteer
+	if volunteers == nil {
+		volunteers = []services.Volunteer{}
+	}
+	writeList(w, volunteers)
+}
+
+// addTodoVolunteerV2 signs a volunteer up for a todo, as the volunteer themselves or the owning
+// organisation. Schedule conflicts are rejected unless 'onConflict=warn' is given, in which
+// case they are returned as warnings.
+func (h *Handlers) addTodoVolunteerV2(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var volunteer services.Volunteer
+	err := json.NewDecoder(r.Body).Decode(&volunteer)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.models.AuthorizeVolunteer(r.Context(), currentUserEmail(r), id, volunteer.VolunteerID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	warnings, err := h.checkVolunteerSchedules(r.Context(), id, []services.Volunteer{volunteer})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if len(warnings) > 0 && r.URL.Query().Get("onConflict") != "warn" {
+		p := newProblem(r, http.StatusConflict, "Volunteer schedule conflict")
+		p.Conflicts = warnings
+		sendProblem(w, p)
+		return
+	}
+
+	err = h.todos.JoinTodo(r.Context(), id, volunteer, currentUserEmail(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// The new volunteer is appended, so it's the last one
+	updated, err := h.todos.GetTodoById(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if n := len(updated.Volunteer); n > 0 {
+		volunteer = updated.Volunteer[n-1]
+	}
+
+	writeData(w, http.StatusCreated, volunteer, &Meta{Count: 1, Warnings: warnings})
+}
+
+// removeTodoVolunteerV2 takes a volunteer off a todo, as the volunteer themselves or the owning
+// organisation
+func (h *Handlers) removeTodoVolunteerV2(w http.ResponseWriter, r *http.Request) {
+	id, volunteerID := chi.URLParam(r, "id"), chi.URLParam(r, "volunteerId")
+
+	if err := h.models.AuthorizeVolunteer(r.Context(), currentUserEmail(r), id, volunteerID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err := h.todos.LeaveTodo(r.Context(), id, volunteerID, currentUserEmail(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) listOrganisationsV2(w http.ResponseWriter, r *http.Request) {
+	organisations, err := h.models.GetOrganisations(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeList(w, organisations)
+}
+
+func (h *Handlers) getOrganisationV2(w http.ResponseWriter, r *http.Request) {
+	organisation, err := h.models.GetOrganisationById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, organisation, nil)
+}
+
+// listOrganisationTodosV2 lists the todos of an organisation. They are found by its ID, so
+// another organisation taking the same name doesn't have its todos listed too.
+func (h *Handlers) listOrganisationTodosV2(w http.ResponseWriter, r *http.Request) {
+	organisation, err := h.models.GetOrganisationById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	todos, err := h.todos.GetTodosByOrganisationID(r.Context(), organisation.ID, organisation.Name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeList(w, todos)
+}
+
+// getCurrentUserV2 returns the profile of the authenticated user, without the password hash
+func (h *Handlers) getCurrentUserV2(w http.ResponseWriter, r *http.Request) {
+	user, err := h.models.GetUserByEmail(r.Context(), currentUserEmail(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user.Password = ""
+	writeData(w, http.StatusOK, user, nil)
+}
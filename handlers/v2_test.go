@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/volunteerService-backend/services"
+)
+
+func TestV2Todos(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.do(request{method: http.MethodPost, path: "/api/v2/todos", body: todoFixture()})
+	expectStatus(t, rec, http.StatusCreated)
+	created := decode[Envelope[services.Todo]](t, rec).Data
+	if created.ID == "" || created.Task != todoFixture().Task {
+		t.Fatalf("got created todo %+v", created)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/api/v2/todos/"+created.ID {
+		t.Errorf("got Location %q", loc)
+	}
+
+	rec = s.get("/api/v2/todos")
+	expectStatus(t, rec, http.StatusOK)
+	list := decode[Envelope[[]services.Todo]](t, rec)
+	if len(list.Data) != 1 || list.Meta == nil || list.Meta.Count != 1 {
+		t.Errorf("got list %+v", list)
+	}
+
+	path := "/api/v2/todos/" + created.ID
+	rec = s.do(request{method: http.MethodPatch, path: path, body: map[string]bool{"completed": true}})
+	expectStatus(t, rec, http.StatusOK)
+	if patched := decode[Envelope[services.Todo]](t, rec).Data; !patched.Completed || patched.Task != created.Task {
+		t.Errorf("got patched todo %+v", patched)
+	}
+
+	expectProblem(t, s.do(request{method: http.MethodPatch, path: path, body: map[string]string{"task": ""}}), http.StatusBadRequest)
+	expectProblem(t, s.do(request{method: http.MethodPatch, path: "/api/v2/todos/000000000000000000000000", body: map[string]bool{}}), http.StatusNotFound)
+
+	rec = s.do(request{method: http.MethodDelete, path: path})
+	expectStatus(t, rec, http.StatusNoContent)
+	if rec.Body.Len() != 0 {
+		t.Errorf("delete returned a body: %s", rec.Body)
+	}
+	expectProblem(t, s.get(path), http.StatusNotFound)
+}
+
+func TestV2TodoVolunteers(t *testing.T) {
+	s := newTestServer(t)
+	_, orgCookie := s.signupAndLogin(organisationFixture())
+	adaID, adaCookie := s.signupAndLogin(volunteerFixture())
+	other := volunteerFixture()
+	other.FirstName, other.LastName = "Grace", "Hopper"
+	other.Email, other.ContactNumber = "grace@example.com", "+447911000003"
+	_, graceCookie := s.signupAndLogin(other)
+	todoID := s.createTodo(todoFixture(), orgCookie)
+	path := "/api/v2/todos/" + todoID + "/volunteers"
+	join := func(cookie *http.Cookie, body interface{}) request {
+		return request{method: http.MethodPost, path: path, body: body, cookies: []*http.Cookie{cookie}}
+	}
+	leave := func(cookie *http.Cookie, todoPath string) request {
+		return request{method: http.MethodDelete, path: todoPath + "/" + adaID, cookies: []*http.Cookie{cookie}}
+	}
+
+	if list := decode[Envelope[[]services.Volunteer]](t, s.get(path)); list.Data == nil || list.Meta.Count != 0 {
+		t.Errorf("new todo has volunteers %+v", list)
+	}
+
+	// Only the volunteer themselves or the owning organisation can sign them up
+	expectProblem(t, s.do(request{method: http.MethodPost, path: path, body: services.Volunteer{VolunteerID: adaID}}), http.StatusUnauthorized)
+	expectProblem(t, s.do(join(graceCookie, services.Volunteer{VolunteerID: adaID})), http.StatusForbidden)
+
+	rec := s.do(join(adaCookie, services.Volunteer{VolunteerID: adaID}))
+	expectStatus(t, rec, http.StatusCreated)
+	joined := decode[Envelope[services.Volunteer]](t, rec).Data
+	if joined.VolunteerID != adaID || joined.JoinedAt.IsZero() {
+		t.Errorf("got joined volunteer %+v", joined)
+	}
+
+	if list := decode[Envelope[[]services.Volunteer]](t, s.get(path)); len(list.Data) != 1 {
+		t.Errorf("got volunteers %+v", list.Data)
+	}
+	expectProblem(t, s.do(join(adaCookie, "{")), http.StatusBadRequest)
+	expectProblem(t, s.do(join(orgCookie, services.Volunteer{VolunteerID: adaID})), http.StatusConflict)
+	p := expectProblem(t, s.do(join(orgCookie, services.Volunteer{})), http.StatusBadRequest)
+	if !hasFieldError(p, "volunteerId") {
+		t.Errorf("got %+v, want a volunteerId field error", p)
+	}
+
+	expectProblem(t, s.do(request{method: http.MethodDelete, path: path + "/" + adaID}), http.StatusUnauthorized)
+	expectProblem(t, s.do(leave(graceCookie, path)), http.StatusForbidden)
+	expectStatus(t, s.do(leave(orgCookie, path)), http.StatusNoContent)
+	if list := decode[Envelope[[]services.Volunteer]](t, s.get(path)); list.Meta.Count != 0 {
+		t.Errorf("got volunteers %+v after leaving", list.Data)
+	}
+	expectProblem(t, s.do(leave(adaCookie, path)), http.StatusNotFound)
+	expectProblem(t, s.do(leave(adaCookie, "/api/v2/todos/missing/volunteers")), http.StatusNotFound)
+}
+
+func TestV2Organisations(t *testing.T) {
+	s := newTestServer(t)
+	orgID, _ := s.signupAndLogin(organisationFixture())
+	s.signup(volunteerFixture())
+	s.createTodo(todoFixture(), nil)
+
+	list := decode[Envelope[[]services.Organisation]](t, s.get("/api/v2/organisations"))
+	if len(list.Data) != 1 || list.Data[0].ID != orgID || list.Data[0].Name != "Helping Hands" {
+		t.Errorf("got organisations %+v", list.Data)
+	}
+
+	rec := s.get("/api/v2/organisations/" + orgID)
+	expectStatus(t, rec, http.StatusOK)
+	if org := decode[Envelope[services.Organisation]](t, rec).Data; org.Type != "charity" {
+		t.Errorf("got organisation %+v", org)
+	}
+
+	todos := decode[Envelope[[]services.Todo]](t, s.get("/api/v2/organisations/"+orgID+"/todos"))
+	if todos.Meta.Count != 1 {
+		t.Errorf("got organisation todos %+v", todos)
+	}
+
+	// Another organisation taking the same name doesn't get the todos of the first
+	twin := organisationFixture()
+	twin.Email, twin.ContactNumber = "twin@example.com", "+447911000004"
+	twinID, _ := s.signupAndLogin(twin)
+	if todos := decode[Envelope[[]services.Todo]](t, s.get("/api/v2/organisations/"+twinID+"/todos")); todos.Meta.Count != 0 {
+		t.Errorf("got todos %+v for an organisation sharing the name", todos.Data)
+	}
+
+	volunteer, err := s.models.GetUserByEmail(context.Background(), volunteerFixture().Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectProblem(t, s.get("/api/v2/organisations/"+volunteer.ID), http.StatusNotFound)
+}
+
+func TestV2CurrentUser(t *testing.T) {
+	s := newTestServer(t)
+	adaID, cookie := s.signupAndLogin(volunteerFixture())
+
+	expectProblem(t, s.get("/api/v2/users/me"), http.StatusUnauthorized)
+
+	rec := s.do(request{method: http.MethodGet, path: "/api/v2/users/me", cookies: []*http.Cookie{cookie}})
+	expectStatus(t, rec, http.StatusOK)
+	me := decode[Envelope[services.User]](t, rec).Data
+	if me.ID != adaID || me.Email != volunteerFixture().Email {
+		t.Errorf("got %+v", me)
+	}
+	if me.Password != "" {
+		t.Error("profile includes the password hash")
+	}
+}
+
+func TestV2AdminBackup(t *testing.T) {
+	s := newTestServer(t)
+	user := volunteerFixture()
+	_, cookie := s.signupAndLogin(user)
+	backup := request{method: http.MethodGet, path: "/api/v2/admin/backup", cookies: []*http.Cookie{cookie}}
+
+	expectProblem(t, s.get("/api/v2/admin/backup"), http.StatusUnauthorized)
+	expectProblem(t, s.do(backup), http.StatusForbidden)
+
+	// Admins get through, but backups are only taken of MongoDB
+	if _, err := s.models.SetAdmin(context.Background(), user.Email, true); err != nil {
+		t.Fatal(err)
+	}
+	expectProblem(t, s.do(backup), http.StatusNotImplemented)
+}
+
+func TestV1Deprecation(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.get("/api/v1/todos")
+	expectStatus(t, rec, http.StatusOK)
+	if rec.Header().Get("Deprecation") == "" || rec.Header().Get("Sunset") == "" {
+		t.Errorf("v1 response lacks deprecation headers: %v", rec.Header())
+	}
+	if link := rec.Header().Get("Link"); link != `</api/v2>; rel="successor-version"` {
+		t.Errorf("got Link %q", link)
+	}
+
+	for _, path := range []string{"/api/v2/todos", "/api/v1/healthcheck"} {
+		if rec := s.get(path); rec.Header().Get("Deprecation") != "" {
+			t.Errorf("%s is marked deprecated", path)
+		}
+	}
+}
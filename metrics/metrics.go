@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const namespace = "volunteer_service"
+
+// HTTP metrics, labelled by chi route pattern so IDs in paths don't explode cardinality
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by method, route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// MongoDB metrics, recorded by the command monitor
+var mongoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "mongo_command_duration_seconds",
+	Help:      "MongoDB command latency, by command name and outcome.",
+	Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"command", "outcome"})
+
+// Domain metrics
+var (
+	Signups = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signups_total",
+		Help:      "Users that signed up.",
+	})
+
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts, by result (success or failure).",
+	}, []string{"result"})
+
+	TodosCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "todos_created_total",
+		Help:      "Todos created.",
+	})
+
+	VolunteerJoins = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "volunteer_joins_total",
+		Help:      "Volunteers added to todos.",
+	})
+)
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware records the count and latency of every request under its chi route pattern
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		// The route pattern is only complete once routing has finished
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		labels := prometheus.Labels{"method": r.Method, "route": route, "status": strconv.Itoa(status)}
+
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// MongoMonitor returns a command monitor that records the latency of every MongoDB command
+func MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			mongoDuration.WithLabelValues(e.CommandName, "success").Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			mongoDuration.WithLabelValues(e.CommandName, "failure").Observe(e.Duration.Seconds())
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// indexNotFound is the server error code for dropping an index that doesn't exist
+const indexNotFound = 27
+
+// CreateIndexes returns an Up step creating indexes on collection. Every index must be named,
+// so the matching DropIndexes can find it; creating an index that already exists is a no-op.
+func CreateIndexes(collection string, indexes ...mongo.IndexModel) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, index := range indexes {
+			if index.Options == nil || index.Options.Name == nil {
+				return fmt.Errorf("index on %s has no name", collection)
+			}
+		}
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, indexes); err != nil {
+			return fmt.Errorf("creating indexes on %s: %w", collection, err)
+		}
+		return nil
+	}
+}
+
+// DropIndexes returns a step dropping the named indexes from collection, skipping any
+// that are already gone
+func DropIndexes(collection string, names ...string) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, name := range names {
+			_, err := db.Collection(collection).Indexes().DropOne(ctx, name)
+			var serverErr mongo.CommandError
+			if errors.As(err, &serverErr) && serverErr.Code == indexNotFound {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("dropping index %s.%s: %w", collection, name, err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+package migrations
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// The collections of the ledger in the migrated database
+const (
+	Collection     = "migrations"
+	LockCollection = "migrations_lock"
+)
+
+// lockTTL is how long a lock is honoured, so one left behind by a crashed process doesn't
+// block migrations forever. It must outlast the slowest migration.
+const lockTTL = 30 * time.Minute
+
+// ledger records the applied versions and keeps two processes from migrating at once
+type ledger interface {
+	// applied returns when each applied version was applied
+	applied(ctx context.Context) (map[int]time.Time, error)
+	record(ctx context.Context, migration Migration, at time.Time) error
+	remove(ctx context.Context, version int) error
+	// lock returns ErrLocked if another process holds the lock
+	lock(ctx context.Context) (unlock func(), err error)
+}
+
+// record is a migration as stored in the migrations collection
+type record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// lockDocument is the single document of the lock collection while it is held
+type lockDocument struct {
+	ID        string    `bson:"_id"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+type mongoLedger struct {
+	migrations *mongo.Collection
+	locks      *mongo.Collection
+}
+
+func newMongoLedger(db *mongo.Database) *mongoLedger {
+	return &mongoLedger{
+		migrations: db.Collection(Collection),
+		locks:      db.Collection(LockCollection),
+	}
+}
+
+func (l *mongoLedger) applied(ctx context.Context) (map[int]time.Time, error) {
+	cursor, err := l.migrations.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	var records []record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := map[int]time.Time{}
+	for _, r := range records {
+		applied[r.Version] = r.AppliedAt
+	}
+	return applied, nil
+}
+
+func (l *mongoLedger) record(ctx context.Context, migration Migration, at time.Time) error {
+	_, err := l.migrations.InsertOne(ctx, record{Version: migration.Version, Description: migration.Description, AppliedAt: at})
+	return err
+}
+
+func (l *mongoLedger) remove(ctx context.Context, version int) error {
+	_, err := l.migrations.DeleteOne(ctx, bson.M{"_id": version})
+	return err
+}
+
+// lock inserts the lock document, taking over one that has expired
+func (l *mongoLedger) lock(ctx context.Context) (func(), error) {
+	doc := lockDocument{ID: "lock", ExpiresAt: time.Now().Add(lockTTL)}
+
+	_, err := l.locks.InsertOne(ctx, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		result, deleteErr := l.locks.DeleteOne(ctx, bson.M{"_id": doc.ID, "expiresAt": bson.M{"$lt": time.Now()}})
+		if deleteErr != nil {
+			return nil, deleteErr
+		}
+		if result.DeletedCount == 0 {
+			return nil, ErrLocked
+		}
+		_, err = l.locks.InsertOne(ctx, doc)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := func() {
+		// Release even when ctx was cancelled midway, rather than leave the lock to expire
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := l.locks.DeleteOne(ctx, bson.M{"_id": doc.ID, "expiresAt": doc.ExpiresAt}); err != nil {
+			slog.Error("Error releasing the migrations lock", "error", err)
+		}
+	}
+	return unlock, nil
+}
@@ -0,0 +1,231 @@
+// Package migrations applies versioned changes to the database schema and data, recording
+// each version applied so every instance and environment converges on the same state.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrLocked is returned when another process is already running migrations
+var ErrLocked = errors.New("migrations are locked by another process")
+
+// Migration is one versioned change. Up applies it and Down reverts it; a nil Down
+// means the migration can't be reverted. Up must be safe to run again on data it has
+// already changed, as restoring a backup replays it over older data.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+	Down        func(ctx context.Context, db *mongo.Database) error
+}
+
+// Status is a migration and when it was applied, if it was
+type Status struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	AppliedAt   *time.Time `json:"appliedAt,omitempty"`
+}
+
+// Migrator runs a set of migrations against a database
+type Migrator struct {
+	db         *mongo.Database
+	ledger     ledger
+	migrations []Migration
+}
+
+// New returns a Migrator for migrations on db, recording the applied versions in its
+// Collection. The migrations must have distinct positive versions and an Up step.
+func New(db *mongo.Database, migrations []Migration) (*Migrator, error) {
+	return newMigrator(db, newMongoLedger(db), migrations)
+}
+
+func newMigrator(db *mongo.Database, ledger ledger, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, migration := range sorted {
+		if migration.Version <= 0 {
+			return nil, fmt.Errorf("migration %q: version must be positive", migration.Description)
+		}
+		if i > 0 && sorted[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("migration %d: duplicate version", migration.Version)
+		}
+		if migration.Up == nil {
+			return nil, fmt.Errorf("migration %d: missing Up step", migration.Version)
+		}
+	}
+
+	return &Migrator{db: db, ledger: ledger, migrations: sorted}, nil
+}
+
+// Latest returns the highest version known, or 0 when there are no migrations
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the newest version applied, or 0 when none is
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	applied, err := m.ledger.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for v := range applied {
+		version = max(version, v)
+	}
+	return version, nil
+}
+
+// Status lists every migration in version order with when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.ledger.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Description: migration.Description}
+		if at, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Pending returns the migrations not applied yet, in version order
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.ledger.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies the pending migrations up to and including target, or all of them when target
+// is 0, in version order. It stops at the first failure and returns the migrations applied.
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	if target == 0 {
+		target = m.Latest()
+	}
+
+	unlock, err := m.ledger.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	applied, err := m.ledger.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		if err := migration.Up(ctx, m.db); err != nil {
+			return done, fmt.Errorf("applying migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+		if err := m.ledger.record(ctx, migration, time.Now()); err != nil {
+			return done, fmt.Errorf("recording migration %d: %w", migration.Version, err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Replay runs the Up step of every migration newer than version again, without recording
+// anything, to bring data restored from that version up to the current schema
+func (m *Migrator) Replay(ctx context.Context, version int) ([]Migration, error) {
+	unlock, err := m.ledger.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if migration.Version <= version {
+			continue
+		}
+		if err := migration.Up(ctx, m.db); err != nil {
+			return done, fmt.Errorf("replaying migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down reverts the applied migrations above target, newest first, or only the newest when
+// target is negative. It stops at the first failure, or at a migration that can't be reverted,
+// and returns the migrations reverted.
+func (m *Migrator) Down(ctx context.Context, target int) ([]Migration, error) {
+	unlock, err := m.ledger.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	applied, err := m.ledger.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if target < 0 {
+		for version := range applied {
+			target = max(target, version-1)
+		}
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version <= target {
+			break
+		}
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if migration.Down == nil {
+			return done, fmt.Errorf("migration %d (%s) can't be reverted", migration.Version, migration.Description)
+		}
+		if err := migration.Down(ctx, m.db); err != nil {
+			return done, fmt.Errorf("reverting migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+		if err := m.ledger.remove(ctx, migration.Version); err != nil {
+			return done, fmt.Errorf("unrecording migration %d: %w", migration.Version, err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
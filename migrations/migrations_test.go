@@ -0,0 +1,226 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// memoryLedger is a ledger kept in memory, standing in for the migrations collection
+type memoryLedger struct {
+	mu       sync.Mutex
+	versions map[int]time.Time
+	locked   bool
+}
+
+func (l *memoryLedger) applied(ctx context.Context) (map[int]time.Time, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	applied := map[int]time.Time{}
+	for version, at := range l.versions {
+		applied[version] = at
+	}
+	return applied, nil
+}
+
+func (l *memoryLedger) record(ctx context.Context, migration Migration, at time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.versions[migration.Version] = at
+	return nil
+}
+
+func (l *memoryLedger) remove(ctx context.Context, version int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.versions, version)
+	return nil
+}
+
+func (l *memoryLedger) lock(ctx context.Context) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locked {
+		return nil, ErrLocked
+	}
+	l.locked = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.locked = false
+	}, nil
+}
+
+// fixture returns migrations 1 to 3 logging their steps to log, where 3 can't be reverted
+// and failing, when set, makes that version's Up fail
+func fixture(log *[]string, failing int) []Migration {
+	step := func(name string, version int) func(context.Context, *mongo.Database) error {
+		return func(context.Context, *mongo.Database) error {
+			if version == failing {
+				return errors.New("boom")
+			}
+			*log = append(*log, name)
+			return nil
+		}
+	}
+
+	return []Migration{
+		{Version: 2, Description: "second", Up: step("up 2", 2), Down: step("down 2", 2)},
+		{Version: 1, Description: "first", Up: step("up 1", 1), Down: step("down 1", 1)},
+		{Version: 3, Description: "third", Up: step("up 3", 3)},
+	}
+}
+
+func versions(migrations []Migration) []int {
+	var v []int
+	for _, migration := range migrations {
+		v = append(v, migration.Version)
+	}
+	return v
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	ledger := &memoryLedger{versions: map[int]time.Time{}}
+	m, err := newMigrator(nil, ledger, fixture(&log, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx, 2)
+	if err != nil || !reflect.DeepEqual(versions(applied), []int{1, 2}) {
+		t.Fatalf("got %v, %v migrating up to 2", versions(applied), err)
+	}
+
+	pending, _ := m.Pending(ctx)
+	if !reflect.DeepEqual(versions(pending), []int{3}) {
+		t.Errorf("got pending %v, want [3]", versions(pending))
+	}
+
+	// Applied versions are skipped
+	if applied, _ := m.Up(ctx, 0); !reflect.DeepEqual(versions(applied), []int{3}) {
+		t.Errorf("got %v migrating to the latest, want [3]", versions(applied))
+	}
+
+	statuses, _ := m.Status(ctx)
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			t.Errorf("got migration %d not applied", status.Version)
+		}
+	}
+
+	// 3 has no Down step, so nothing is reverted
+	reverted, err := m.Down(ctx, 1)
+	if err == nil || len(reverted) != 0 {
+		t.Errorf("got %v, %v reverting an irreversible migration", versions(reverted), err)
+	}
+
+	if want := []string{"up 1", "up 2", "up 3"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("got steps %v, want %v", log, want)
+	}
+}
+
+func TestMigratorDown(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	ledger := &memoryLedger{versions: map[int]time.Time{}}
+	migrations := fixture(&log, 0)[:2]
+	m, _ := newMigrator(nil, ledger, migrations)
+
+	m.Up(ctx, 0)
+
+	// A negative target goes one step back
+	reverted, err := m.Down(ctx, -1)
+	if err != nil || !reflect.DeepEqual(versions(reverted), []int{2}) {
+		t.Fatalf("got %v, %v going one step back", versions(reverted), err)
+	}
+
+	m.Up(ctx, 0)
+	reverted, err = m.Down(ctx, 0)
+	if err != nil || !reflect.DeepEqual(versions(reverted), []int{2, 1}) {
+		t.Fatalf("got %v, %v reverting everything", versions(reverted), err)
+	}
+	if want := []string{"up 1", "up 2", "down 2", "up 2", "down 2", "down 1"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("got steps %v, want %v", log, want)
+	}
+	if pending, _ := m.Pending(ctx); len(pending) != 2 {
+		t.Errorf("got %d pending after reverting, want 2", len(pending))
+	}
+}
+
+func TestMigratorReplay(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	ledger := &memoryLedger{versions: map[int]time.Time{}}
+	m, _ := newMigrator(nil, ledger, fixture(&log, 0))
+
+	m.Up(ctx, 0)
+	if version, _ := m.Version(ctx); version != 3 {
+		t.Errorf("got version %d, want 3", version)
+	}
+
+	// Data restored from version 1 gets the later steps again, while the ledger is untouched
+	replayed, err := m.Replay(ctx, 1)
+	if err != nil || !reflect.DeepEqual(versions(replayed), []int{2, 3}) {
+		t.Fatalf("got %v, %v replaying from 1", versions(replayed), err)
+	}
+	if want := []string{"up 1", "up 2", "up 3", "up 2", "up 3"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("got steps %v, want %v", log, want)
+	}
+	if pending, _ := m.Pending(ctx); len(pending) != 0 {
+		t.Errorf("got %d pending after replaying, want 0", len(pending))
+	}
+}
+
+func TestMigratorFailure(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	ledger := &memoryLedger{versions: map[int]time.Time{}}
+	m, _ := newMigrator(nil, ledger, fixture(&log, 2))
+
+	applied, err := m.Up(ctx, 0)
+	if err == nil || !reflect.DeepEqual(versions(applied), []int{1}) {
+		t.Fatalf("got %v, %v, want to stop after 1", versions(applied), err)
+	}
+	if pending, _ := m.Pending(ctx); !reflect.DeepEqual(versions(pending), []int{2, 3}) {
+		t.Errorf("got pending %v, want [2 3]", versions(pending))
+	}
+}
+
+func TestMigratorLocked(t *testing.T) {
+	ctx := context.Background()
+	ledger := &memoryLedger{versions: map[int]time.Time{}, locked: true}
+	m, _ := newMigrator(nil, ledger, fixture(new([]string), 0))
+
+	if _, err := m.Up(ctx, 0); !errors.Is(err, ErrLocked) {
+		t.Errorf("got %v, want ErrLocked", err)
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	up := func(context.Context, *mongo.Database) error { return nil }
+
+	tests := []struct {
+		name       string
+		migrations []Migration
+	}{
+		{"zero version", []Migration{{Version: 0, Up: up}}},
+		{"duplicate version", []Migration{{Version: 1, Up: up}, {Version: 1, Up: up}}},
+		{"missing up", []Migration{{Version: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newMigrator(nil, &memoryLedger{}, tt.migrations); err == nil {
+				t.Error("got no error")
+			}
+		})
+	}
+}
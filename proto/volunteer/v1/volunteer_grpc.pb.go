@@ -0,0 +1,449 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: volunteer/v1/volunteer.proto
+
+package volunteerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VolunteerService_ListTodos_FullMethodName  = "/volunteer.v1.VolunteerService/ListTodos"
+	VolunteerService_GetTodo_FullMethodName    = "/volunteer.v1.VolunteerService/GetTodo"
+	VolunteerService_CreateTodo_FullMethodName = "/volunteer.v1.VolunteerService/CreateTodo"
+	VolunteerService_UpdateTodo_FullMethodName = "/volunteer.v1.VolunteerService/UpdateTodo"
+	VolunteerService_DeleteTodo_FullMethodName = "/volunteer.v1.VolunteerService/DeleteTodo"
+	VolunteerService_JoinTodo_FullMethodName   = "/volunteer.v1.VolunteerService/JoinTodo"
+	VolunteerService_LeaveTodo_FullMethodName  = "/volunteer.v1.VolunteerService/LeaveTodo"
+	VolunteerService_GetUsers_FullMethodName   = "/volunteer.v1.VolunteerService/GetUsers"
+	VolunteerService_WatchTodos_FullMethodName = "/volunteer.v1.VolunteerService/WatchTodos"
+)
+
+// VolunteerServiceClient is the client API for VolunteerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VolunteerService exposes todos and users to internal Go services over gRPC.
+// Every call must carry a JWT, as issued by the REST login, in the
+// "authorization" metadata as "Bearer <token>".
+type VolunteerServiceClient interface {
+	// ListTodos lists todos, optionally filtered
+	ListTodos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*ListTodosResponse, error)
+	GetTodo(ctx context.Context, in *GetTodoRequest, opts ...grpc.CallOption) (*Todo, error)
+	CreateTodo(ctx context.Context, in *CreateTodoRequest, opts ...grpc.CallOption) (*Todo, error)
+	// UpdateTodo changes the task or completion of a todo
+	UpdateTodo(ctx context.Context, in *UpdateTodoRequest, opts ...grpc.CallOption) (*Todo, error)
+	DeleteTodo(ctx context.Context, in *DeleteTodoRequest, opts ...grpc.CallOption) (*DeleteTodoResponse, error)
+	// JoinTodo signs a volunteer up for a todo. Schedule conflicts fail with
+	// FAILED_PRECONDITION unless allow_conflicts is set.
+	JoinTodo(ctx context.Context, in *JoinTodoRequest, opts ...grpc.CallOption) (*JoinTodoResponse, error)
+	// LeaveTodo takes a volunteer off a todo
+	LeaveTodo(ctx context.Context, in *LeaveTodoRequest, opts ...grpc.CallOption) (*Todo, error)
+	GetUsers(ctx context.Context, in *GetUsersRequest, opts ...grpc.CallOption) (*GetUsersResponse, error)
+	// WatchTodos streams every change to todos as it happens, until the client cancels
+	WatchTodos(ctx context.Context, in *WatchTodosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TodoEvent], error)
+}
+
+type volunteerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVolunteerServiceClient(cc grpc.ClientConnInterface) VolunteerServiceClient {
+	return &volunteerServiceClient{cc}
+}
+
+func (c *volunteerServiceClient) ListTodos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*ListTodosResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTodosResponse)
+	err := c.cc.Invoke(ctx, VolunteerService_ListTodos_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volunteerServiceClient) GetTodo(ctx context.Context, in *GetTodoRequest, opts ...grpc.CallOption) (*Todo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Todo)
+	err := c.cc.Invoke(ctx, VolunteerService_GetTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volunteerServiceClient) CreateTodo(ctx context.Context, in *CreateTodoRequest, opts ...grpc.CallOption) (*Todo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Todo)
+	err := c.cc.Invoke(ctx, VolunteerService_CreateTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volunteerServiceClient) UpdateTodo(ctx context.Context, in *UpdateTodoRequest, opts ...grpc.CallOption) (*Todo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Todo)
+	err := c.cc.Invoke(ctx, VolunteerService_UpdateTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volunteerServiceClient) DeleteTodo(ctx context.Context, in *DeleteTodoRequest, opts ...grpc.CallOption) (*DeleteTodoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTodoResponse)
+	err := c.cc.Invoke(ctx, VolunteerService_DeleteTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volunteerServiceClient) JoinTodo(ctx context.Context, in *JoinTodoRequest, opts ...grpc.CallOption) (*JoinTodoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JoinTodoResponse)
+	err := c.cc.Invoke(ctx, VolunteerService_JoinTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volunteerServiceClient) LeaveTodo(ctx context.Context, in *LeaveTodoRequest, opts ...grpc.CallOption) (*Todo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Todo)
+	err := c.cc.Invoke(ctx, VolunteerService_LeaveTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volunteerServiceClient) GetUsers(ctx context.Context, in *GetUsersRequest, opts ...grpc.CallOption) (*GetUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsersResponse)
+	err := c.cc.Invoke(ctx, VolunteerService_GetUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volunteerServiceClient) WatchTodos(ctx context.Context, in *WatchTodosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TodoEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VolunteerService_ServiceDesc.Streams[0], VolunteerService_WatchTodos_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTodosRequest, TodoEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VolunteerService_WatchTodosClient = grpc.ServerStreamingClient[TodoEvent]
+
+// VolunteerServiceServer is the server API for VolunteerService service.
+// All implementations must embed UnimplementedVolunteerServiceServer
+// for forward compatibility.
+//
+// VolunteerService exposes todos and users to internal Go services over gRPC.
+// Every call must carry a JWT, as issued by the REST login, in the
+// "authorization" metadata as "Bearer <token>".
+type VolunteerServiceServer interface {
+	// ListTodos lists todos, optionally filtered
+	ListTodos(context.Context, *ListTodosRequest) (*ListTodosResponse, error)
+	GetTodo(context.Context, *GetTodoRequest) (*Todo, error)
+	CreateTodo(context.Context, *CreateTodoRequest) (*Todo, error)
+	// UpdateTodo changes the task or completion of a todo
+	UpdateTodo(context.Context, *UpdateTodoRequest) (*Todo, error)
+	DeleteTodo(context.Context, *DeleteTodoRequest) (*DeleteTodoResponse, error)
+	// JoinTodo signs a volunteer up for a todo. Schedule conflicts fail with
+	// FAILED_PRECONDITION unless allow_conflicts is set.
+	JoinTodo(context.Context, *JoinTodoRequest) (*JoinTodoResponse, error)
+	// LeaveTodo takes a volunteer off a todo
+	LeaveTodo(context.Context, *LeaveTodoRequest) (*Todo, error)
+	GetUsers(context.Context, *GetUsersRequest) (*GetUsersResponse, error)
+	// WatchTodos streams every change to todos as it happens, until the client cancels
+	WatchTodos(*WatchTodosRequest, grpc.ServerStreamingServer[TodoEvent]) error
+	mustEmbedUnimplementedVolunteerServiceServer()
+}
+
+// UnimplementedVolunteerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVolunteerServiceServer struct{}
+
+func (UnimplementedVolunteerServiceServer) ListTodos(context.Context, *ListTodosRequest) (*ListTodosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodos not implemented")
+}
+func (UnimplementedVolunteerServiceServer) GetTodo(context.Context, *GetTodoRequest) (*Todo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTodo not implemented")
+}
+func (UnimplementedVolunteerServiceServer) CreateTodo(context.Context, *CreateTodoRequest) (*Todo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTodo not implemented")
+}
+func (UnimplementedVolunteerServiceServer) UpdateTodo(context.Context, *UpdateTodoRequest) (*Todo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTodo not implemented")
+}
+func (UnimplementedVolunteerServiceServer) DeleteTodo(context.Context, *DeleteTodoRequest) (*DeleteTodoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodo not implemented")
+}
+func (UnimplementedVolunteerServiceServer) JoinTodo(context.Context, *JoinTodoRequest) (*JoinTodoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinTodo not implemented")
+}
+func (UnimplementedVolunteerServiceServer) LeaveTodo(context.Context, *LeaveTodoRequest) (*Todo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaveTodo not implemented")
+}
+func (UnimplementedVolunteerServiceServer) GetUsers(context.Context, *GetUsersRequest) (*GetUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsers not implemented")
+}
+func (UnimplementedVolunteerServiceServer) WatchTodos(*WatchTodosRequest, grpc.ServerStreamingServer[TodoEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTodos not implemented")
+}
+func (UnimplementedVolunteerServiceServer) mustEmbedUnimplementedVolunteerServiceServer() {}
+func (UnimplementedVolunteerServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeVolunteerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VolunteerServiceServer will
+// result in compilation errors.
+type UnsafeVolunteerServiceServer interface {
+	mustEmbedUnimplementedVolunteerServiceServer()
+}
+
+func RegisterVolunteerServiceServer(s grpc.ServiceRegistrar, srv VolunteerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVolunteerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VolunteerService_ServiceDesc, srv)
+}
+
+func _VolunteerService_ListTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolunteerServiceServer).ListTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VolunteerService_ListTodos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolunteerServiceServer).ListTodos(ctx, req.(*ListTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolunteerService_GetTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolunteerServiceServer).GetTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VolunteerService_GetTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolunteerServiceServer).GetTodo(ctx, req.(*GetTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolunteerService_CreateTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolunteerServiceServer).CreateTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VolunteerService_CreateTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolunteerServiceServer).CreateTodo(ctx, req.(*CreateTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolunteerService_UpdateTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolunteerServiceServer).UpdateTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VolunteerService_UpdateTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolunteerServiceServer).UpdateTodo(ctx, req.(*UpdateTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolunteerService_DeleteTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolunteerServiceServer).DeleteTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VolunteerService_DeleteTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolunteerServiceServer).DeleteTodo(ctx, req.(*DeleteTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolunteerService_JoinTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolunteerServiceServer).JoinTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VolunteerService_JoinTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolunteerServiceServer).JoinTodo(ctx, req.(*JoinTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolunteerService_LeaveTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolunteerServiceServer).LeaveTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VolunteerService_LeaveTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolunteerServiceServer).LeaveTodo(ctx, req.(*LeaveTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolunteerService_GetUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolunteerServiceServer).GetUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VolunteerService_GetUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolunteerServiceServer).GetUsers(ctx, req.(*GetUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolunteerService_WatchTodos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTodosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VolunteerServiceServer).WatchTodos(m, &grpc.GenericServerStream[WatchTodosRequest, TodoEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VolunteerService_WatchTodosServer = grpc.ServerStreamingServer[TodoEvent]
+
+// VolunteerService_ServiceDesc is the grpc.ServiceDesc for VolunteerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VolunteerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "volunteer.v1.VolunteerService",
+	HandlerType: (*VolunteerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTodos",
+			Handler:    _VolunteerService_ListTodos_Handler,
+		},
+		{
+			MethodName: "GetTodo",
+			Handler:    _VolunteerService_GetTodo_Handler,
+		},
+		{
+			MethodName: "CreateTodo",
+			Handler:    _VolunteerService_CreateTodo_Handler,
+		},
+		{
+			MethodName: "UpdateTodo",
+			Handler:    _VolunteerService_UpdateTodo_Handler,
+		},
+		{
+			MethodName: "DeleteTodo",
+			Handler:    _VolunteerService_DeleteTodo_Handler,
+		},
+		{
+			MethodName: "JoinTodo",
+			Handler:    _VolunteerService_JoinTodo_Handler,
+		},
+		{
+			MethodName: "LeaveTodo",
+			Handler:    _VolunteerService_LeaveTodo_Handler,
+		},
+		{
+			MethodName: "GetUsers",
+			Handler:    _VolunteerService_GetUsers_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTodos",
+			Handler:       _VolunteerService_WatchTodos_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "volunteer/v1/volunteer.proto",
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+)
+
+// Limiter is a token bucket for each key, named so limiters can share a store
+type Limiter struct {
+	store Store
+	name  string
+	rate  float64 // tokens added per second
+	burst float64
+	now   func() time.Time
+}
+
+// NewLimiter returns a limiter allowing limit.Burst requests at once for each key,
+// refilled at limit.Requests every limit.Per
+func NewLimiter(store Store, name string, limit config.RateLimit) *Limiter {
+	return &Limiter{
+		store: store,
+		name:  name,
+		rate:  float64(limit.Requests) / limit.Per.Seconds(),
+		burst: float64(limit.Burst),
+		now:   time.Now,
+	}
+}
+
+// Result is the outcome of a request against a bucket
+type Result struct {
+	Allowed bool
+	// Limit is the size of the bucket and Remaining the requests left in it
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, zero when Allowed
+	RetryAfter time.Duration
+}
+
+// Policy describes the limit as a RateLimit-Policy header value: the burst and the seconds to refill it
+func (l *Limiter) Policy() string {
+	return fmt.Sprintf("%d;w=%d", int(l.burst), int(math.Ceil(l.burst/l.rate)))
+}
+
+// Allow takes a token from the bucket of key, if there is one
+func (l *Limiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := l.now()
+	fill := time.Duration(l.burst / l.rate * float64(time.Second))
+
+	var tokens float64
+	var allowed bool
+	err := l.store.Update(ctx, l.name+":"+key, fill, func(s *State) {
+		tokens = l.burst
+		if !s.Updated.IsZero() {
+			tokens = math.Min(l.burst, s.Tokens+now.Sub(s.Updated).Seconds()*l.rate)
+		}
+
+		allowed = tokens >= 1
+		if allowed {
+			tokens--
+		}
+		s.Tokens, s.Updated = tokens, now
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{
+		Allowed:   allowed,
+		Limit:     int(l.burst),
+		Remaining: int(tokens),
+		Reset:     l.wait(l.burst - tokens),
+	}
+	if !allowed {
+		res.RetryAfter = l.wait(1 - tokens)
+	}
+	return res, nil
+}
+
+// wait is how long the bucket takes to gain tokens
+func (l *Limiter) wait(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
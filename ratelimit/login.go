@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+)
+
+// LoginGuard slows down guessing passwords: after each failed login for an email the next
+// attempt must wait, twice as long each time, and too many failures lock the email out
+type LoginGuard struct {
+	store Store
+	cfg   config.LoginLimitConfig
+	now   func() time.Time
+}
+
+// NewLoginGuard returns a guard keeping its counters in store
+func NewLoginGuard(store Store, cfg config.LoginLimitConfig) *LoginGuard {
+	return &LoginGuard{store: store, cfg: cfg, now: time.Now}
+}
+
+// key is the same for every spelling of an email
+func loginKey(email string) string {
+	return "login:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// Wait returns how long until email may try to log in again, zero if it may now
+func (g *LoginGuard) Wait(ctx context.Context, email string) (time.Duration, error) {
+	state, err := g.store.Get(ctx, loginKey(email))
+	if err != nil {
+		return 0, err
+	}
+
+	return max(state.Blocked.Sub(g.now()), 0), nil
+}
+
+// Failed records a failed login for email and returns how long until it may try again
+func (g *LoginGuard) Failed(ctx context.Context, email string) (time.Duration, error) {
+	now := g.now()
+
+	var wait time.Duration
+	err := g.store.Update(ctx, loginKey(email), g.cfg.Lockout, func(s *State) {
+		s.Failures++
+		wait = g.delay(s.Failures)
+		if s.Failures >= g.cfg.MaxFailures {
+			wait = g.cfg.Lockout
+		}
+		s.Blocked, s.Updated = now.Add(wait), now
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return wait, nil
+}
+
+// Succeeded forgets the failures of email
+func (g *LoginGuard) Succeeded(ctx context.Context, email string) error {
+	return g.store.Delete(ctx, loginKey(email))
+}
+
+// delay is the wait after the given number of failures in a row
+func (g *LoginGuard) delay(failures int) time.Duration {
+	delay := g.cfg.Delay
+	for i := 1; i < failures && delay < g.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, g.cfg.MaxDelay)
+}
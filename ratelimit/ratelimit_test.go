@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+)
+
+// clock is a fake time source moved on by the tests
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: time.Now()}
+	limiter := NewLimiter(NewMemoryStore(), "test", config.RateLimit{Requests: 1, Per: time.Second, Burst: 3})
+	limiter.now = c.Now
+
+	for i := 2; i >= 0; i-- {
+		res, err := limiter.Allow(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Allowed || res.Remaining != i || res.Limit != 3 {
+			t.Fatalf("got %+v, want allowed with %d remaining", res, i)
+		}
+	}
+
+	res, _ := limiter.Allow(ctx, "a")
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 3*time.Second {
+		t.Errorf("got %+v for an empty bucket, want retry after 1s and full after 3s", res)
+	}
+
+	// Other keys have their own bucket
+	if res, _ := limiter.Allow(ctx, "b"); !res.Allowed {
+		t.Errorf("got %+v for another key", res)
+	}
+
+	// One token is added every second, never beyond the burst
+	c.now = c.now.Add(1500 * time.Millisecond)
+	if res, _ := limiter.Allow(ctx, "a"); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("got %+v after refilling 1.5 tokens", res)
+	}
+	c.now = c.now.Add(time.Hour)
+	if res, _ := limiter.Allow(ctx, "a"); !res.Allowed || res.Remaining != 2 {
+		t.Errorf("got %+v after refilling past the burst", res)
+	}
+
+	if policy := limiter.Policy(); policy != "3;w=3" {
+		t.Errorf("got policy %q", policy)
+	}
+}
+
+func TestLoginGuard(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: time.Now()}
+	guard := NewLoginGuard(NewMemoryStore(), config.LoginLimitConfig{
+		Delay:       time.Second,
+		MaxDelay:    3 * time.Second,
+		MaxFailures: 4,
+		Lockout:     time.Hour,
+	})
+	guard.now = c.Now
+
+	// Waits double after each failure up to the maximum, then the email is locked out
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, time.Hour} {
+		wait, err := guard.Failed(ctx, "Ada@Example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wait != want {
+			t.Errorf("got wait %v, want %v", wait, want)
+		}
+	}
+
+	if wait, _ := guard.Wait(ctx, " ada@example.com"); wait != time.Hour {
+		t.Errorf("got wait %v for the same email spelled differently, want 1h", wait)
+	}
+	if wait, _ := guard.Wait(ctx, "grace@example.com"); wait != 0 {
+		t.Errorf("got wait %v for another email", wait)
+	}
+
+	c.now = c.now.Add(time.Hour)
+	if wait, _ := guard.Wait(ctx, "ada@example.com"); wait != 0 {
+		t.Errorf("got wait %v after the lockout", wait)
+	}
+
+	if err := guard.Succeeded(ctx, "ada@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if wait, _ := guard.Failed(ctx, "ada@example.com"); wait != time.Second {
+		t.Errorf("got wait %v after a success, want the failures forgotten", wait)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore().(*memoryStore)
+	store.Update(ctx, "short", time.Second, func(s *State) { s.Failures = 1 })
+	store.Update(ctx, "long", time.Hour, func(s *State) { s.Failures = 1 })
+
+	store.sweep(time.Now().Add(time.Minute))
+	if _, ok := store.entries["short"]; ok {
+		t.Error("got the expired key kept")
+	}
+	if _, ok := store.entries["long"]; !ok {
+		t.Error("got the live key dropped")
+	}
+
+	// Sweep returns once its worker is stopped
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := store.Sweep(ctx); err != nil {
+		t.Errorf("got %v stopping the sweep", err)
+	}
+}
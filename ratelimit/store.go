@@ -0,0 +1,35 @@
+// Package ratelimit throttles clients with token buckets and slows down repeated failed logins.
+// Counters live in a Store, in memory for a single instance or in MongoDB to share them.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// State is what a Store keeps for each key
+type State struct {
+	// Tokens left in a bucket as of Updated
+	Tokens float64 `bson:"tokens"`
+	// Failures in a row, for login attempts
+	Failures int `bson:"failures"`
+	// Blocked is when the key may next be tried, for login attempts
+	Blocked time.Time `bson:"blocked"`
+	// Updated is when the state was last changed, zero for a key not seen before
+	Updated time.Time `bson:"updated"`
+}
+
+// Store keeps the counters. Update must apply fn to the state of a key atomically with respect
+// to other updates of that key; it may call fn more than once, so fn must only depend on the state.
+// A key is forgotten ttl after its last update.
+type Store interface {
+	Get(ctx context.Context, key string) (State, error)
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(*State)) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Sweeper is a Store that must be swept of expired keys in the background, rather than
+// the database dropping them. Sweep runs until ctx is cancelled.
+type Sweeper interface {
+	Sweep(ctx context.Context) error
+}
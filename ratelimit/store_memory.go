@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store drops expired keys
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	state   State
+	expires time.Time
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+// NewMemoryStore returns a Store keeping the counters of this instance only.
+// It is a Sweeper, so expired keys are only dropped while Sweep runs.
+func NewMemoryStore() Store {
+	return &memoryStore{entries: map[string]memoryEntry{}}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return State{}, nil
+	}
+	return entry.state, nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(*State)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := s.entries[key]
+	if !ok || now.After(entry.expires) {
+		entry = memoryEntry{}
+	}
+	fn(&entry.state)
+	entry.expires = now.Add(ttl)
+	s.entries[key] = entry
+
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// Sweep drops the expired keys every sweepInterval until ctx is cancelled
+func (s *memoryStore) Sweep(ctx context.Context) error {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			s.sweep(now)
+		}
+	}
+}
+
+// sweep drops the keys expired at now
+func (s *memoryStore) sweep(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.entries {
+		if now.After(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
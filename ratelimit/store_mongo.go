@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxUpdateAttempts bounds the retries of an update that keeps racing other instances
+const maxUpdateAttempts = 10
+
+// mongoDocument is a key's state with the version used for optimistic concurrency
+type mongoDocument struct {
+	Key       string    `bson:"_id"`
+	State     State     `bson:"state"`
+	Version   int64     `bson:"version"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+type mongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore returns a Store sharing the counters between instances through collection,
+// creating the TTL index that drops expired keys
+func NewMongoStore(ctx context.Context, collection *mongo.Collection) (Store, error) {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating rate limit TTL index: %w", err)
+	}
+
+	return &mongoStore{collection: collection}, nil
+}
+
+// find returns the live document for key, or a new one if there is none.
+// The TTL monitor only runs every minute, so expired documents are treated as missing here.
+func (s *mongoStore) find(ctx context.Context, key string) (mongoDocument, bool, error) {
+	var doc mongoDocument
+	err := s.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return mongoDocument{Key: key}, false, nil
+	}
+	if err != nil {
+		return mongoDocument{}, false, err
+	}
+
+	if time.Now().After(doc.ExpiresAt) {
+		doc.State = State{}
+	}
+	return doc, true, nil
+}
+
+func (s *mongoStore) Get(ctx context.Context, key string) (State, error) {
+	doc, _, err := s.find(ctx, key)
+	return doc.State, err
+}
+
+// Update reads the state, applies fn and writes it back only if no one else wrote it in between,
+// retrying otherwise
+func (s *mongoStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(*State)) error {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		doc, found, err := s.find(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		fn(&doc.State)
+		doc.ExpiresAt = time.Now().Add(ttl)
+
+		if !found {
+			doc.Version = 1
+			_, err := s.collection.InsertOne(ctx, doc)
+			if mongo.IsDuplicateKeyError(err) {
+				continue
+			}
+			return err
+		}
+
+		result, err := s.collection.UpdateOne(ctx,
+			bson.M{"_id": key, "version": doc.Version},
+			bson.M{
+				"$set": bson.M{"state": doc.State, "expiresAt": doc.ExpiresAt},
+				"$inc": bson.M{"version": 1},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 1 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("updating rate limit %q: too much contention", key)
+}
+
+func (s *mongoStore) Delete(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/volunteerService-backend/logging"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CreateAdmin signs user up and gives them the admin role, returning the stored user
+func (m *Models) CreateAdmin(ctx context.Context, user User) (User, error) {
+	if _, err := m.Signup(ctx, user); err != nil {
+		return User{}, err
+	}
+
+	return m.SetAdmin(ctx, user.Email, true)
+}
+
+// SetAdmin grants or revokes the admin role of the user with email, returning the updated user
+func (m *Models) SetAdmin(ctx context.Context, email string, admin bool) (User, error) {
+	user, err := m.GetUserByEmail(ctx, email)
+	if err != nil {
+		return User{}, err
+	}
+
+	user.Role = ""
+	if admin {
+		user.Role = RoleAdmin
+	}
+	if err := m.users.SetRole(ctx, user.ID, user.Role); err != nil {
+		logging.FromContext(ctx).Error("Error setting role", "error", err)
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+// ResetPassword replaces the password of the user with email, after checking it like at signup
+func (m *Models) ResetPassword(ctx context.Context, email, password string) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+
+	user, err := m.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error hashing password", "error", err)
+		return err
+	}
+	if err := m.users.SetPassword(ctx, user.ID, string(hashedPassword)); err != nil {
+		logging.FromContext(ctx).Error("Error setting password", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetDeactivated deactivates or reactivates the user with email, returning the updated user.
+// Deactivated users can't log in, and the tokens already issued to them are rejected.
+func (m *Models) SetDeactivated(ctx context.Context, email string, deactivated bool) (User, error) {
+	user, err := m.GetUserByEmail(ctx, email)
+	if err != nil {
+		return User{}, err
+	}
+
+	user.DeactivatedAt = nil
+	if deactivated {
+		now := time.Now().UTC()
+		user.DeactivatedAt = &now
+	}
+	if err := m.users.SetDeactivatedAt(ctx, user.ID, user.DeactivatedAt); err != nil {
+		logging.FromContext(ctx).Error("Error setting deactivation", "error", err)
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+// PurgeTodosByOrg deletes every todo of the organisations called orgName, recording each
+// deletion in the todo's history, and returns how many were deleted
+func (m *Models) PurgeTodosByOrg(ctx context.Context, orgName, actor string) (int, error) {
+	orgIDs, err := m.findOrganisationIDs(ctx, orgName)
+	if err != nil {
+		return 0, err
+	}
+
+	todos, err := m.todos.ListByOrganisation(ctx, orgIDs, orgName)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding todos by orgName", "error", err)
+		return 0, err
+	}
+
+	deleted := 0
+	for _, todo := range todos {
+		if err := m.DeleteTodo(ctx, todo.ID, actor); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/volunteerService-backend/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AnalyticsRange holds the organisation and date range an analytics query is scoped to.
+// The organisation is picked out by its ID, as names aren't unique; its name only matches
+// older todos that carry no ID.
+type AnalyticsRange struct {
+	OrganisationID   string
+	OrganisationName string
+	From             time.Time
+	To               time.Time
+}
+
+// StatusBucket is the number of open and completed todos in one time bucket
+type StatusBucket struct {
+	Bucket    time.Time `json:"bucket" bson:"_id"`
+	Open      int       `json:"open" bson:"open"`
+	Completed int       `json:"completed" bson:"completed"`
+	Total     int       `json:"total" bson:"total"`
+}
+
+// FillStats describes how well an organisation's todos are staffed
+type FillStats struct {
+	Tasks             int     `json:"tasks" bson:"tasks"`
+	FilledTasks       int     `json:"filledTasks" bson:"filledTasks"`
+	AverageFillRate   float64 `json:"avgFillRate" bson:"avgFillRate"`
+	AverageTimeToFill float64 `json:"avgTimeToFillHours" bson:"avgTimeToFillHours"`
+}
+
+// VolTypeCount is the number of distinct volunteers that joined todos of one VolunteerType
+type VolTypeCount struct {
+	VolunteerType string `json:"volType" bson:"_id"`
+	Volunteers    int    `json:"volunteers" bson:"volunteers"`
+	Joins         int    `json:"joins" bson:"joins"`
+}
+
+// RepeatStats describes how many volunteers come back for more than one todo
+type RepeatStats struct {
+	Volunteers          int     `json:"volunteers" bson:"volunteers"`
+	RepeatVolunteers    int     `json:"repeatVolunteers" bson:"repeatVolunteers"`
+	RepeatVolunteerRate float64 `json:"repeatVolunteerRate" bson:"-"`
+}
+
+// analyticsBuckets are the units accepted for status bucketing
+var analyticsBuckets = map[string]bool{"day": true, "week": true, "month": true}
+
+// matchStage builds the $match stage shared by every analytics pipeline
+func matchStage(r AnalyticsRange) bson.D {
+	filter := organisationMatch([]string{r.OrganisationID}, r.OrganisationName)
+	timeFilter := bson.M{}
+	if !r.From.IsZero() {
+		timeFilter["$gte"] = r.From
+	}
+	if !r.To.IsZero() {
+		timeFilter["$lt"] = r.To
+	}
+	if len(timeFilter) > 0 {
+		filter["time"] = timeFilter
+	}
+
+	return bson.D{{Key: "$match", Value: filter}}
+}
+
+// runAggregation runs a pipeline on the 'todos' collection and decodes every result into out
+func (m *Models) runAggregation(ctx context.Context, pipeline mongo.Pipeline, out interface{}) error {
+	ctx, cancel := m.deadlines.aggregate(ctx)
+	defer cancel()
+
+	collection := m.client.Database(m.database).Collection("todos")
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error running aggregation", "error", err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, out); err != nil {
+		logging.FromContext(ctx).Error("Error decoding aggregation results", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetStatusOverTime counts open and completed todos per day, week or month, by day when
+// bucket is empty
+func (m *Models) GetStatusOverTime(ctx context.Context, r AnalyticsRange, bucket string) ([]StatusBucket, error) {
+	if bucket == "" {
+		bucket = "day"
+	}
+	if !analyticsBuckets[bucket] {
+		return nil, Invalid(FieldError{Field: "bucket", Message: "must be one of day, week or month"})
+	}
+	if !m.UsesMongo() {
+		todos, err := m.rangeTodos(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		return statusOverTime(todos, bucket), nil
+	}
+
+	match := matchStage(r)
+
+	pipeline := mongo.Pipeline{
+		match,
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$dateTrunc": bson.M{"date": "$time", "unit": bucket}},
+			"completed": bson.M{"$sum": bson.M{
+				"$cond": bson.A{"$completed", 1, 0},
+			}},
+			"open": bson.M{"$sum": bson.M{
+				"$cond": bson.A{"$completed", 0, 1},
+			}},
+			"total": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	buckets := []StatusBucket{}
+	if err := m.runAggregation(ctx, pipeline, &buckets); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// GetFillStats reports the average fill rate and time-to-fill of an organisation's todos.
+// A todo without VolunteersNeeded is treated as needing a single volunteer, and todos
+// without CreatedAt only count towards the fill rate.
+func (m *Models) GetFillStats(ctx context.Context, r AnalyticsRange) (FillStats, error) {
+	if !m.UsesMongo() {
+		todos, err := m.rangeTodos(ctx, r)
+		if err != nil {
+			return FillStats{}, err
+		}
+		return fillStats(todos), nil
+	}
+
+	match := matchStage(r)
+
+	pipeline := mongo.Pipeline{
+		match,
+		{{Key: "$project", Value: bson.M{
+			"needed":    bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$volNeeded", 0}}, 1}},
+			"joined":    bson.M{"$size": bson.M{"$ifNull": bson.A{"$volunteer", bson.A{}}}},
+			"joinedAt":  bson.M{"$ifNull": bson.A{"$volunteer.joinedAt", bson.A{}}},
+			"createdAt": 1,
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"fill":   bson.M{"$min": bson.A{1, bson.M{"$divide": bson.A{"$joined", "$needed"}}}},
+			"filled": bson.M{"$gte": bson.A{"$joined", "$needed"}},
+			// the needed-th volunteer to join is the one who filled the todo. Todos from before
+			// createdAt was recorded are left out, as there's nothing to measure from.
+			"timeToFill": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{
+					bson.M{"$gte": bson.A{"$joined", "$needed"}},
+					bson.M{"$gt": bson.A{"$createdAt", nil}},
+				}},
+				bson.M{"$subtract": bson.A{
+					bson.M{"$arrayElemAt": bson.A{"$joinedAt", bson.M{"$subtract": bson.A{"$needed", 1}}}},
+					"$createdAt",
+				}},
+				nil,
+			}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":                nil,
+			"tasks":              bson.M{"$sum": 1},
+			"filledTasks":        bson.M{"$sum": bson.M{"$cond": bson.A{"$filled", 1, 0}}},
+			"avgFillRate":        bson.M{"$avg": "$fill"},
+			"avgTimeToFillHours": bson.M{"$avg": bson.M{"$divide": bson.A{"$timeToFill", 1000 * 60 * 60}}},
+		}}},
+	}
+
+	var stats []FillStats
+	if err := m.runAggregation(ctx, pipeline, &stats); err != nil {
+		return FillStats{}, err
+	}
+	if len(stats) == 0 {
+		return FillStats{}, nil
+	}
+
+	return stats[0], nil
+}
+
+// GetVolunteerCountsByType counts distinct volunteers per VolunteerType of the todos they joined
+func (m *Models) GetVolunteerCountsByType(ctx context.Context, r AnalyticsRange) ([]VolTypeCount, error) {
+	if !m.UsesMongo() {
+		todos, err := m.rangeTodos(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		return volunteerCountsByType(todos), nil
+	}
+
+	match := matchStage(r)
+
+	pipeline := mongo.Pipeline{
+		match,
+		{{Key: "$unwind", Value: "$volunteer"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$volType",
+			"volunteers": bson.M{"$addToSet": "$volunteer.volunteerId"},
+			"joins":      bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"volunteers": bson.M{"$size": "$volunteers"},
+			"joins":      1,
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	counts := []VolTypeCount{}
+	if err := m.runAggregation(ctx, pipeline, &counts); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// GetRepeatVolunteerStats reports how many volunteers joined more than one of an organisation's todos
+func (m *Models) GetRepeatVolunteerStats(ctx context.Context, r AnalyticsRange) (RepeatStats, error) {
+	if !m.UsesMongo() {
+		todos, err := m.rangeTodos(ctx, r)
+		if err != nil {
+			return RepeatStats{}, err
+		}
+		return repeatVolunteerStats(todos).withRate(), nil
+	}
+
+	match := matchStage(r)
+
+	pipeline := mongo.Pipeline{
+		match,
+		{{Key: "$unwind", Value: "$volunteer"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$volunteer.volunteerId",
+			"tasks": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        nil,
+			"volunteers": bson.M{"$sum": 1},
+			"repeatVolunteers": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$gt": bson.A{"$tasks", 1}}, 1, 0},
+			}},
+		}}},
+	}
+
+	var stats []RepeatStats
+	if err := m.runAggregation(ctx, pipeline, &stats); err != nil {
+		return RepeatStats{}, err
+	}
+	if len(stats) == 0 {
+		return RepeatStats{}, nil
+	}
+
+	return stats[0].withRate(), nil
+}
+
+// withRate returns s with RepeatVolunteerRate worked out from its counts
+func (s RepeatStats) withRate() RepeatStats {
+	if s.Volunteers > 0 {
+		s.RepeatVolunteerRate = float64(s.RepeatVolunteers) / float64(s.Volunteers)
+	}
+	return s
+}
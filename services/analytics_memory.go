@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// The memory storage backend has no aggregation pipeline, so the analytics are worked out
+// here over the organisation's todos, with the same results the pipelines give.
+
+// rangeTodos returns the todos an analytics range covers
+func (m *Models) rangeTodos(ctx context.Context, r AnalyticsRange) ([]Todo, error) {
+	todos, err := m.todos.ListByOrganisation(ctx, []string{r.OrganisationID}, r.OrganisationName)
+	if err != nil {
+		return nil, err
+	}
+	if r.From.IsZero() && r.To.IsZero() {
+		return todos, nil
+	}
+
+	// As with the $match stage, todos without a time fall outside any range
+	var inRange []Todo
+	for _, todo := range todos {
+		if todo.Time.IsZero() || (!r.From.IsZero() && todo.Time.Before(r.From)) || (!r.To.IsZero() && !todo.Time.Before(r.To)) {
+			continue
+		}
+		inRange = append(inRange, todo)
+	}
+	return inRange, nil
+}
+
+// truncate returns the start of the day, week or month holding t in UTC, like $dateTrunc,
+// whose weeks start on Sunday
+func truncate(t time.Time, bucket string) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case "week":
+		return day.AddDate(0, 0, -int(day.Weekday()))
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// statusOverTime counts open and completed todos per bucket, oldest first
+func statusOverTime(todos []Todo, bucket string) []StatusBucket {
+	byBucket := map[time.Time]*StatusBucket{}
+	for _, todo := range todos {
+		start := truncate(todo.Time, bucket)
+		b, ok := byBucket[start]
+		if !ok {
+			b = &StatusBucket{Bucket: start}
+			byBucket[start] = b
+		}
+		if todo.Completed {
+			b.Completed++
+		} else {
+			b.Open++
+		}
+		b.Total++
+	}
+
+	buckets := []StatusBucket{}
+	for _, b := range byBucket {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Bucket.Before(buckets[j].Bucket) })
+	return buckets
+}
+
+// fillStats works out the fill rate and time-to-fill of todos, as GetFillStats describes
+func fillStats(todos []Todo) FillStats {
+	var stats FillStats
+	var fillRates, hoursToFill float64
+	var timed int
+	for _, todo := range todos {
+		needed := max(todo.VolunteersNeeded, 1)
+		joined := len(todo.Volunteer)
+
+		stats.Tasks++
+		fillRates += min(1, float64(joined)/float64(needed))
+		if joined < needed {
+			continue
+		}
+		stats.FilledTasks++
+
+		// The needed-th volunteer to join is the one who filled the todo
+		filledAt := todo.Volunteer[needed-1].JoinedAt
+		if !todo.CreatedAt.IsZero() && !filledAt.IsZero() {
+			hoursToFill += filledAt.Sub(todo.CreatedAt).Hours()
+			timed++
+		}
+	}
+
+	if stats.Tasks > 0 {
+		stats.AverageFillRate = fillRates / float64(stats.Tasks)
+	}
+	if timed > 0 {
+		stats.AverageTimeToFill = hoursToFill / float64(timed)
+	}
+	return stats
+}
+
+// volunteerCountsByType counts the distinct volunteers and joins per volunteer type of todos
+func volunteerCountsByType(todos []Todo) []VolTypeCount {
+	volunteers := map[string]map[string]bool{}
+	joins := map[string]int{}
+	for _, todo := range todos {
+		for _, volunteer := range todo.Volunteer {
+			if volunteers[todo.VolunteerType] == nil {
+				volunteers[todo.VolunteerType] = map[string]bool{}
+			}
+			volunteers[todo.VolunteerType][volunteer.VolunteerID] = true
+			joins[todo.VolunteerType]++
+		}
+	}
+
+	counts := []VolTypeCount{}
+	for volType, ids := range volunteers {
+		counts = append(counts, VolTypeCount{VolunteerType: volType, Volunteers: len(ids), Joins: joins[volType]})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].VolunteerType < counts[j].VolunteerType })
+	return counts
+}
+
+// repeatVolunteerStats counts the volunteers of todos, and those who joined more than one
+func repeatVolunteerStats(todos []Todo) RepeatStats {
+	tasks := map[string]int{}
+	for _, todo := range todos {
+		for _, volunteer := range todo.Volunteer {
+			tasks[volunteer.VolunteerID]++
+		}
+	}
+
+	stats := RepeatStats{Volunteers: len(tasks)}
+	for _, n := range tasks {
+		if n > 1 {
+			stats.RepeatVolunteers++
+		}
+	}
+	return stats
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFillStats(t *testing.T) {
+	created := time.Date(2030, time.January, 7, 9, 0, 0, 0, time.UTC)
+	joined := func(hours ...int) []Volunteer {
+		var volunteers []Volunteer
+		for i, h := range hours {
+			volunteers = append(volunteers, Volunteer{VolunteerID: string(rune('a' + i)), JoinedAt: created.Add(time.Duration(h) * time.Hour)})
+		}
+		return volunteers
+	}
+
+	stats := fillStats([]Todo{
+		// filled by its second volunteer after 4 hours
+		{CreatedAt: created, VolunteersNeeded: 2, Volunteer: joined(1, 4)},
+		// no VolunteersNeeded counts as needing one
+		{CreatedAt: created, Volunteer: joined(2)},
+		// filled, but from before createdAt was recorded
+		{VolunteersNeeded: 1, Volunteer: joined(100)},
+		{VolunteersNeeded: 4, Volunteer: joined(1)},
+	})
+
+	want := FillStats{Tasks: 4, FilledTasks: 3, AverageFillRate: 3.25 / 4, AverageTimeToFill: 3}
+	if stats != want {
+		t.Errorf("got %+v, want %+v", stats, want)
+	}
+	if stats := fillStats(nil); stats != (FillStats{}) {
+		t.Errorf("got %+v for no todos", stats)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	// A Wednesday evening in New York is already Thursday in UTC
+	at := time.Date(2030, time.January, 9, 22, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	tests := map[string]time.Time{
+		"day":   time.Date(2030, time.January, 10, 0, 0, 0, 0, time.UTC),
+		"week":  time.Date(2030, time.January, 6, 0, 0, 0, 0, time.UTC),
+		"month": time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for bucket, want := range tests {
+		if got := truncate(at, bucket); !got.Equal(want) {
+			t.Errorf("got %v for %s, want %v", got, bucket, want)
+		}
+	}
+}
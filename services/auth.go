@@ -2,132 +2,199 @@ package services
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/metrics"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // User struct for storing user data
 type User struct {
-	ID               string `json:"id,omitempty" bson:"_id,omitempty"`
-	FirstName        string `json:"firstName,omitempty" bson:"firstName,omitempty"`
-	LastName         string `json:"lastName,omitempty" bson:"lastName,omitempty"`
-	Email            string `json:"email,omitempty" bson:"email,omitempty"`
-	Password         string `json:"password,omitempty" bson:"password,omitempty"`
-	ContactNumber    string `json:"contactNo,omitempty" bson:"contactNo,omitempty"`
-	UserType         string `json:"userType,omitempty" bson:"userType,omitempty"`
-	VolunteerType    string `json:"volType,omitempty" bson:"volType,omitempty"`
-	OrganisationName string `json:"orgName" bson:"orgName"`
-	OrganisationType string `json:"orgType" bson:"orgType"`
+	ID               string        `json:"id,omitempty" bson:"_id,omitempty"`
+	FirstName        string        `json:"firstName,omitempty" bson:"firstName,omitempty" validate:"required_if=UserType volunteer,max=100"`
+	LastName         string        `json:"lastName,omitempty" bson:"lastName,omitempty" validate:"max=100"`
+	Email            string        `json:"email,omitempty" bson:"email,omitempty" validate:"required,email,max=254"`
+	Password         string        `json:"password,omitempty" bson:"password,omitempty" validate:"required,password"`
+	ContactNumber    string        `json:"contactNo,omitempty" bson:"contactNo,omitempty" validate:"required,e164"`
+	UserType         string        `json:"userType,omitempty" bson:"userType,omitempty" validate:"required,usertype"`
+	VolunteerType    string        `json:"volType,omitempty" bson:"volType,omitempty" validate:"omitempty,voltype"`
+	OrganisationName string        `json:"orgName" bson:"orgName" validate:"required_if=UserType organisation,max=200"`
+	OrganisationType string        `json:"orgType" bson:"orgType" validate:"omitempty,orgtype"`
+	Availability     *Availability `json:"availability,omitempty" bson:"availability,omitempty"`
+	Role             string        `json:"role,omitempty" bson:"role,omitempty"`
+	DeactivatedAt    *time.Time    `json:"deactivatedAt,omitempty" bson:"deactivatedAt,omitempty"`
 }
 
-// JWT secret key used for signing JWT tokens (should be stored in environment variable in production)
-var jwtSecret = []byte("your-secret-key") // Replace with a secure key in production
+// RoleAdmin is the role of operators, who can manage any account and the stored data.
+// Roles grant rights on top of the user type, and are only set from volctl.
+const RoleAdmin = "admin"
 
-// NewAuthService initializes the Mongo client for authentication
-func NewAuthService(mongo *mongo.Client) {
-	client = mongo
+// IsAdmin reports whether the user has the admin role
+func (u User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// LoginResponse is the profile returned by a successful login.
+// Unlike everywhere else the user's ID is sent as "ID", which existing clients rely on.
+type LoginResponse struct {
+	UserType      string `json:"userType"`
+	ID            string `json:"ID"`
+	FirstName     string `json:"firstName"`
+	LastName      string `json:"lastName"`
+	ContactNumber string `json:"contactNo"`
+	Email         string `json:"email"`
+	OrgName       string `json:"orgName"`
+	OrgType       string `json:"orgType"`
+	VolType       string `json:"volType"`
+}
+
+// Cookies set at login
+const (
+	AuthCookie = "auth_token"
+	// CSRFCookie holds the token state-changing requests authenticated by AuthCookie must echo
+	// in the CSRFHeader. It's readable by scripts, unlike AuthCookie.
+	CSRFCookie = "csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// sameSiteModes maps the configured SameSite values to the cookie attribute
+var sameSiteModes = map[string]http.SameSite{
+	config.SameSiteLax:    http.SameSiteLaxMode,
+	config.SameSiteStrict: http.SameSiteStrictMode,
+	config.SameSiteNone:   http.SameSiteNoneMode,
+}
+
+// CSRFToken returns the CSRF token for the session of authToken. It's derived from the token
+// with the JWT secret, so a site that can't read the auth cookie can't produce it, and no
+// server-side state is needed to check it.
+func (m *Models) CSRFToken(authToken string) string {
+	mac := hmac.New(sha256.New, m.jwtSecret)
+	mac.Write([]byte("csrf:" + authToken))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
 }
 
 // Signup handles user registration by hashing the password and saving user data
-func Signup(user User) (string, error) {
-	collection := returnCollectionPointer("users")
+func (m *Models) Signup(ctx context.Context, user User) (string, error) {
+	if err := user.Validate(); err != nil {
+		return "", err
+	}
+
+	// Roles and deactivation are only ever set by operators
+	user.Role = ""
+	user.DeactivatedAt = nil
 
 	// Check if the email already exists
-	emailResult := collection.FindOne(context.TODO(), bson.M{"email": user.Email})
-	if emailResult.Err() == nil {
-		log.Println("Email already exists")
-		return "", fmt.Errorf("email already exists")
-	} else if emailResult.Err() != mongo.ErrNoDocuments {
-		log.Println("Error checking for email existence:", emailResult.Err())
-		return "", emailResult.Err()
+	_, err := m.users.GetByEmail(ctx, user.Email)
+	if err == nil {
+		logging.FromContext(ctx).Warn("Email already exists")
+		return "", Conflict("email already exists")
+	} else if !errors.Is(err, ErrNotFound) {
+		logging.FromContext(ctx).Error("Error checking for email existence", "error", err)
+		return "", err
 	}
 
 	// Check if the contact number already exists
-	contactResult := collection.FindOne(context.TODO(), bson.M{"contactNo": user.ContactNumber})
-	if contactResult.Err() == nil {
-		log.Println("Contact number already exists")
-		return "", fmt.Errorf("contact number already exists")
-	} else if contactResult.Err() != mongo.ErrNoDocuments {
-		log.Println("Error checking for contact number existence:", contactResult.Err())
-		return "", contactResult.Err()
+	_, err = m.users.GetByContactNumber(ctx, user.ContactNumber)
+	if err == nil {
+		logging.FromContext(ctx).Warn("Contact number already exists")
+		return "", Conflict("contact number already exists")
+	} else if !errors.Is(err, ErrNotFound) {
+		logging.FromContext(ctx).Error("Error checking for contact number existence", "error", err)
+		return "", err
 	}
 
 	// Hash the password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Println("Error hashing password:", err)
+		logging.FromContext(ctx).Error("Error hashing password", "error", err)
 		return "", err
 	}
 	user.Password = string(hashedPassword)
 
-	// Insert the user into the MongoDB "users" collection
-	_, err = collection.InsertOne(context.TODO(), user)
+	// Store the user
+	_, err = m.users.Insert(ctx, user)
 	if err != nil {
-		log.Println("Error inserting user:", err)
+		logging.FromContext(ctx).Error("Error inserting user", "error", err)
 		return "", err
 	}
 
+	metrics.Signups.Inc()
+
 	// Return userType as JSON response, no need to send token during signup
 	return user.UserType, nil
 }
 
 // Login handles user login by verifying the password and returning a JWT token
-func Login(w http.ResponseWriter, email, password string) (string, error) {
-	// Find the user in the MongoDB "users" collection
-	collection := returnCollectionPointer("users")
-	var user User
-	err := collection.FindOne(context.TODO(), bson.M{"email": email}).Decode(&user)
+func (m *Models) Login(ctx context.Context, w http.ResponseWriter, email, password string) (string, error) {
+	// Find the user by email
+	user, err := m.users.GetByEmail(ctx, email)
 	if err != nil {
-		log.Println("Error finding user:", err)
-		return "", fmt.Errorf("invalid credentials")
+		logging.FromContext(ctx).Error("Error finding user", "error", err)
+		metrics.Logins.WithLabelValues("failure").Inc()
+		return "", Unauthorized("invalid credentials")
 	}
 
 	// Compare the hashed password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		log.Println("Invalid credentials")
-		return "", fmt.Errorf("invalid credentials")
+		logging.FromContext(ctx).Warn("Invalid credentials")
+		metrics.Logins.WithLabelValues("failure").Inc()
+		return "", Unauthorized("invalid credentials")
+	}
+
+	// Only tell whoever knows the password that the account is deactivated
+	if user.DeactivatedAt != nil {
+		logging.FromContext(ctx).Warn("Login to a deactivated account")
+		metrics.Logins.WithLabelValues("failure").Inc()
+		return "", Forbidden("account is deactivated")
 	}
+	metrics.Logins.WithLabelValues("success").Inc()
 
 	// Generate JWT token
-	token, err := generateJWT(user.Email)
+	token, err := m.generateJWT(user.Email)
 	if err != nil {
-		log.Println("Error generating JWT:", err)
+		logging.FromContext(ctx).Error("Error generating JWT", "error", err)
 		return "", err
 	}
 
 	// Set the token as an HTTPOnly cookie
+	expires := time.Now().Add(m.tokenTTL)
 	http.SetCookie(w, &http.Cookie{
-		Name:     "auth_token",
+		Name:     AuthCookie,
 		Value:    token,
 		HttpOnly: true, // Make the cookie accessible only through HTTP requests (can't be accessed via JavaScript)
 		Secure:   true, // Should be true if you're using HTTPS
+		SameSite: m.cookieSameSite,
 		Path:     "/",
-		Expires:  time.Now().Add(time.Hour * 24), // Token expires in 24 hours
+		Expires:  expires,
 	})
 
+	// The CSRF token is readable so the frontend can echo it, from the cookie or, when the
+	// frontend is on another site, from the header
+	csrfToken := m.CSRFToken(token)
+	http.SetCookie(w, &http.Cookie{
+		Name:     CSRFCookie,
+		Value:    csrfToken,
+		Secure:   true,
+		SameSite: m.cookieSameSite,
+		Path:     "/",
+		Expires:  expires,
+	})
+	w.Header().Set(CSRFHeader, csrfToken)
+
 	// Return the userType as JSON response
-	response := struct {
-		UserType      string `json:"userType"`
-		ID            string `json:"ID"`
-		FirstName     string `json:"firstName"`
-		LastName      string `json:"lastName"`
-		ContactNumber string `json:"contactNo"`
-		Email         string `json:"email"`
-		OrgName       string `json:"orgName"`
-		OrgType       string `json:"orgType"`
-		VolType       string `json:"volType"`
-	}{
+	response := LoginResponse{
 		UserType:      user.UserType,
 		ID:            user.ID,
 		FirstName:     user.FirstName,
@@ -144,7 +211,7 @@ func Login(w http.ResponseWriter, email, password string) (string, error) {
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
-		log.Println("Error encoding JSON response:", err)
+		logging.FromContext(ctx).Error("Error encoding JSON response", "error", err)
 		return "", err
 	}
 
@@ -152,62 +219,142 @@ func Login(w http.ResponseWriter, email, password string) (string, error) {
 }
 
 // generateJWT generates a JWT token for the user
-func generateJWT(email string) (string, error) {
+func (m *Models) generateJWT(email string) (string, error) {
 	// Create the claims
 	claims := jwt.MapClaims{
 		"email": email,
-		"exp":   time.Now().Add(time.Hour * 24).Unix(), // Token expires in 24 hours
+		"exp":   time.Now().Add(m.tokenTTL).Unix(),
 	}
 
 	// Create the token with claims and the secret key
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// Sign the token and return it
-	signedToken, err := token.SignedString(jwtSecret)
+	signedToken, err := token.SignedString(m.jwtSecret)
 	if err != nil {
-		log.Println("Error signing token:", err)
-		return "", err
+		return "", fmt.Errorf("signing token: %w", err)
 	}
 
 	return signedToken, nil
 }
 
+// ParseJWT validates a JWT token and returns the email it was issued for
+func (m *Models) ParseJWT(tokenString string) (string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return m.jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return "", fmt.Errorf("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", fmt.Errorf("invalid token")
+	}
+	email, ok := claims["email"].(string)
+	if !ok || email == "" {
+		return "", fmt.Errorf("invalid token")
+	}
+
+	return email, nil
+}
+
+// Authenticate validates a JWT token and returns the email of its user. Tokens of users that
+// have since been deactivated or removed fail with ErrUnauthorized, like invalid ones.
+func (m *Models) Authenticate(ctx context.Context, token string) (string, error) {
+	email, err := m.ParseJWT(token)
+	if err != nil {
+		return "", Unauthorized("invalid token")
+	}
+
+	user, err := m.users.GetByEmail(ctx, email)
+	if errors.Is(err, ErrNotFound) {
+		return "", Unauthorized("invalid token")
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding the user of a token", "error", err)
+		return "", err
+	}
+	if user.DeactivatedAt != nil {
+		return "", Unauthorized("account is deactivated")
+	}
+
+	return email, nil
+}
+
+// GetUserByEmail retrieves a single user by their email
+func (m *Models) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	user, err := m.users.GetByEmail(ctx, email)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding user", "error", err)
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+// AuthorizeUser checks that the user with email, the authenticated caller, is the user userID,
+// for changes users may only make to themselves
+func (m *Models) AuthorizeUser(ctx context.Context, email, userID string) error {
+	if email == "" {
+		return Unauthorized("Authentication required")
+	}
+	user, err := m.users.GetByEmail(ctx, email)
+	if err != nil {
+		return Unauthorized("Authentication required")
+	}
+
+	if user.ID != userID {
+		return Forbidden("Users can only change their own details")
+	}
+
+	return nil
+}
+
 // GetUsersByID retrieves user details by multiple IDs
-func GetUsersByID(ids []string) ([]User, error) {
-	collection := returnCollectionPointer("users")
-	var users []User
-	// Convert string slice to BSON array of ObjectIDs
-	var objectIDs []primitive.ObjectID
+func (m *Models) GetUsersByID(ctx context.Context, ids []string) ([]User, error) {
 	for _, id := range ids {
-		objectID, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			log.Println("Invalid ID format:", id)
-			return nil, fmt.Errorf("invalid ID format: %s", id)
+		if !primitive.IsValidObjectID(id) {
+			logging.FromContext(ctx).Warn("Invalid ID format", "id", id)
+			return nil, Invalid(FieldError{Field: "id", Message: "invalid ID format: " + id})
 		}
-		objectIDs = append(objectIDs, objectID)
 	}
-	// Query to find all users with the given IDs
-	filter := bson.M{"_id": bson.M{"$in": objectIDs}}
-	cursor, err := collection.Find(context.TODO(), filter, options.Find())
+
+	users, err := m.users.ListByIDs(ctx, ids)
 	if err != nil {
-		log.Println("Error finding users:", err)
+		logging.FromContext(ctx).Error("Error finding users", "error", err)
 		return nil, err
 	}
-	// Iterate through the cursor and decode each user
-	for cursor.Next(context.TODO()) {
-		var user User
-		err := cursor.Decode(&user)
-		if err != nil {
-			log.Println("Error decoding user:", err)
-			return nil, err
+
+	return users, nil
+}
+
+// LoadUsers fetches the users among ids in a single batched query and returns them keyed by ID.
+// Malformed IDs, as may be stored on older todos, are skipped rather than failing the batch.
+func (m *Models) LoadUsers(ctx context.Context, ids []string) (map[string]User, error) {
+	var valid []string
+	for _, id := range ids {
+		if primitive.IsValidObjectID(id) {
+			valid = append(valid, id)
 		}
-		users = append(users, user)
 	}
-	if err := cursor.Err(); err != nil {
-		log.Println("Cursor error:", err)
+
+	users := map[string]User{}
+	if len(valid) == 0 {
+		return users, nil
+	}
+
+	found, err := m.users.ListByIDs(ctx, valid)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error loading users", "error", err)
 		return nil, err
 	}
-	// Close the cursor once finished
-	cursor.Close(context.TODO())
+	for _, user := range found {
+		users[user.ID] = user
+	}
+
 	return users, nil
 }
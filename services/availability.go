@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/volunteerService-backend/logging"
+)
+
+// defaultTaskDuration is how long a todo is assumed to last when it has no EndTime
+const defaultTaskDuration = time.Hour
+
+// AvailabilityWindow is a weekly time range, e.g. Monday 09:00 - 17:00
+type AvailabilityWindow struct {
+	Day   time.Weekday `json:"day" bson:"day"`
+	Start string       `json:"start" bson:"start"` // HH:MM
+	End   string       `json:"end" bson:"end"`     // HH:MM
+}
+
+// Availability stores when a volunteer can take on tasks
+type Availability struct {
+	Timezone  string               `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	Windows   []AvailabilityWindow `json:"windows" bson:"windows"`
+	Blackouts []string             `json:"blackouts" bson:"blackouts"` // YYYY-MM-DD
+}
+
+// End returns when the todo finishes, falling back to defaultTaskDuration after its start
+func (t Todo) End() time.Time {
+	if !t.EndTime.IsZero() {
+		return t.EndTime
+	}
+	return t.Time.Add(defaultTaskDuration)
+}
+
+// location returns the timezone the availability is expressed in, defaulting to UTC
+func (a Availability) location() *time.Location {
+	if a.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(a.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// clockMinutes parses a HH:MM time of day into minutes since midnight.
+// A single digit hour, as in "9:00", is accepted.
+func clockMinutes(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validate checks the timezone, window times and blackout dates are well formed
+func (a Availability) Validate() error {
+	var fields []FieldError
+
+	if a.Timezone != "" {
+		if _, err := time.LoadLocation(a.Timezone); err != nil {
+			fields = append(fields, FieldError{Field: "timezone", Message: "unknown timezone " + a.Timezone})
+		}
+	}
+
+	for i, window := range a.Windows {
+		field := fmt.Sprintf("windows[%d]", i)
+		if window.Day < time.Sunday || window.Day > time.Saturday {
+			fields = append(fields, FieldError{Field: field + ".day", Message: "must be between 0 (Sunday) and 6 (Saturday)"})
+		}
+		start, err := clockMinutes(window.Start)
+		if err != nil {
+			fields = append(fields, FieldError{Field: field + ".start", Message: "must be a HH:MM time"})
+		}
+		end, endErr := clockMinutes(window.End)
+		if endErr != nil {
+			fields = append(fields, FieldError{Field: field + ".end", Message: "must be a HH:MM time"})
+		}
+		if err == nil && endErr == nil && start >= end {
+			fields = append(fields, FieldError{Field: field, Message: "start must be before end"})
+		}
+	}
+
+	for i, day := range a.Blackouts {
+		if _, err := time.Parse("2006-01-02", day); err != nil {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("blackouts[%d]", i), Message: "must be a YYYY-MM-DD date"})
+		}
+	}
+
+	if len(fields) > 0 {
+		return Invalid(fields...)
+	}
+
+	return nil
+}
+
+// Fits reports whether a task running from start to end falls inside the availability.
+// A volunteer without any windows is treated as always available outside of blackouts.
+func (a Availability) Fits(start, end time.Time) bool {
+	loc := a.location()
+	start, end = start.In(loc), end.In(loc)
+
+	for _, day := range a.Blackouts {
+		if start.Format("2006-01-02") == day || end.Format("2006-01-02") == day {
+			return false
+		}
+	}
+
+	if len(a.Windows) == 0 {
+		return true
+	}
+
+	// Tasks spanning midnight can't fit in a single weekly window
+	if start.Format("2006-01-02") != end.Format("2006-01-02") {
+		return false
+	}
+
+	// Compared as minutes, since as strings "9:00" would sort after "10:30"
+	startMinutes := start.Hour()*60 + start.Minute()
+	endMinutes := end.Hour()*60 + end.Minute()
+	for _, window := range a.Windows {
+		if window.Day != start.Weekday() {
+			continue
+		}
+		windowStart, err := clockMinutes(window.Start)
+		if err != nil {
+			continue
+		}
+		windowEnd, err := clockMinutes(window.End)
+		if err != nil {
+			continue
+		}
+		if windowStart <= startMinutes && endMinutes <= windowEnd {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetAvailability returns the availability stored for a user
+func (m *Models) GetAvailability(ctx context.Context, userID string) (Availability, error) {
+	user, err := m.users.Get(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding user", "error", err)
+		return Availability{}, err
+	}
+
+	if user.Availability == nil {
+		return Availability{Windows: []AvailabilityWindow{}, Blackouts: []string{}}, nil
+	}
+
+	return *user.Availability, nil
+}
+
+// SetAvailability replaces the weekly windows and blackout dates of a user
+func (m *Models) SetAvailability(ctx context.Context, userID string, availability Availability) error {
+	if err := availability.Validate(); err != nil {
+		return err
+	}
+
+	err := m.users.SetAvailability(ctx, userID, availability)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error updating availability", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// CheckSchedule returns a message for every way joining the todo would clash with the
+// volunteer's schedule: overlapping another joined todo or falling outside availability
+func (m *Models) CheckSchedule(ctx context.Context, todo Todo, volunteerID string) ([]string, error) {
+	var problems []string
+
+	start, end := todo.Time, todo.End()
+
+	overlapping, err := m.todos.ListOverlapping(ctx, volunteerID, start, end, todo.ID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding overlapping todos", "error", err)
+		return nil, err
+	}
+	for _, other := range overlapping {
+		problems = append(problems, fmt.Sprintf("overlaps with task '%s' at %s", other.Task, other.Time.Format(time.RFC3339)))
+	}
+
+	availability, err := m.GetAvailability(ctx, volunteerID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if err == nil && !availability.Fits(start, end) {
+		problems = append(problems, "falls outside the volunteer's availability")
+	}
+
+	return problems, nil
+}
+
+// FilterByAvailability keeps only the todos that fit the user's availability
+func (m *Models) FilterByAvailability(ctx context.Context, todos []Todo, userID string) ([]Todo, error) {
+	availability, err := m.GetAvailability(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fitting := []Todo{}
+	for _, todo := range todos {
+		if availability.Fits(todo.Time, todo.End()) {
+			fitting = append(fitting, todo)
+		}
+	}
+
+	return fitting, nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAvailabilityFits(t *testing.T) {
+	// 2030-01-07 is a Monday
+	at := func(hour, minute int) time.Time {
+		return time.Date(2030, time.January, 7, hour, minute, 0, 0, time.UTC)
+	}
+	mornings := Availability{Windows: []AvailabilityWindow{{Day: time.Monday, Start: "9:00", End: "12:30"}}}
+
+	tests := []struct {
+		name         string
+		availability Availability
+		start, end   time.Time
+		want         bool
+	}{
+		{"inside a one digit hour window", mornings, at(9, 30), at(10, 30), true},
+		{"exactly the window", mornings, at(9, 0), at(12, 30), true},
+		{"starts before the window", mornings, at(8, 30), at(10, 0), false},
+		{"ends after the window", mornings, at(12, 0), at(13, 0), false},
+		{"another day", mornings, at(9, 0), at(10, 0).AddDate(0, 0, 1), false},
+		{"no windows", Availability{}, at(22, 0), at(23, 0), true},
+		{"blackout", Availability{Blackouts: []string{"2030-01-07"}}, at(9, 0), at(10, 0), false},
+		{"spans midnight", mornings, at(23, 0), at(1, 0).AddDate(0, 0, 1), false},
+		{"in the volunteer's timezone", Availability{
+			Timezone: "America/New_York",
+			Windows:  []AvailabilityWindow{{Day: time.Monday, Start: "09:00", End: "17:00"}},
+		}, at(15, 0), at(16, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.availability.Fits(tt.start, tt.end); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailabilityValidate(t *testing.T) {
+	valid := Availability{Windows: []AvailabilityWindow{{Day: time.Friday, Start: "9:00", End: "10:30"}}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("got %v for a one digit hour window", err)
+	}
+
+	// As strings "9:00" sorts after "10:30", so this must be rejected by value
+	backwards := Availability{Windows: []AvailabilityWindow{{Day: time.Friday, Start: "10:30", End: "9:00"}}}
+	if err := backwards.Validate(); !errors.Is(err, ErrValidation) {
+		t.Errorf("got %v for a window ending before it starts", err)
+	}
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/volunteerService-backend/backup"
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/migrations"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RateLimitCollection holds the rate limiting counters when they are shared through MongoDB
+const RateLimitCollection = "rateLimits"
+
+// backupSkipped are the collections of short-lived state left out of backups
+var backupSkipped = map[string]bool{
+	migrations.LockCollection: true,
+	RateLimitCollection:       true,
+}
+
+// restoreBatchSize is how many documents a full restore inserts at a time
+const restoreBatchSize = 500
+
+// ExportBackup writes every collection of the database to w as a backup archive,
+// including collections added after this code was written
+func (m *Models) ExportBackup(ctx context.Context, w io.Writer) (backup.Manifest, error) {
+	migrator, err := m.NewMigrator()
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+	version, err := migrator.Version(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error reading the schema version", "error", err)
+		return backup.Manifest{}, err
+	}
+
+	db := m.client.Database(m.database)
+	readCtx, cancel := m.deadlines.read(ctx)
+	names, err := db.ListCollectionNames(readCtx, bson.D{})
+	cancel()
+	if err != nil {
+		logging.FromContext(ctx).Error("Error listing collections", "error", err)
+		return backup.Manifest{}, err
+	}
+	sort.Strings(names)
+
+	archive := backup.NewWriter(w, version)
+	for _, name := range names {
+		if backupSkipped[name] {
+			continue
+		}
+		if err := m.exportCollection(ctx, archive, db.Collection(name)); err != nil {
+			logging.FromContext(ctx).Error("Error exporting collection", "collection", name, "error", err)
+			return backup.Manifest{}, err
+		}
+	}
+
+	return archive.Close()
+}
+
+// exportCollection streams one collection into archive in _id order. Each batch is bounded
+// by the read deadline rather than the whole scan, so large collections can be exported.
+func (m *Models) exportCollection(ctx context.Context, archive *backup.Writer, collection *mongo.Collection) error {
+	out, err := archive.Collection(collection.Name())
+	if err != nil {
+		return err
+	}
+
+	findCtx, cancel := m.deadlines.read(ctx)
+	cursor, err := collection.Find(findCtx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.Background())
+
+	for {
+		nextCtx, cancel := m.deadlines.read(ctx)
+		next := cursor.Next(nextCtx)
+		cancel()
+		if !next {
+			break
+		}
+		if err := out.Write(cursor.Current); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// RestoreResult reports what a restore wrote
+type RestoreResult struct {
+	// SchemaVersion is the schema version of the archive
+	SchemaVersion int `json:"schemaVersion"`
+	// Documents counts the documents written to each collection
+	Documents map[string]int64 `json:"documents"`
+	// Migrated lists the migrations applied to bring the data up to date
+	Migrated []int `json:"migrated"`
+}
+
+// RestoreBackup restores archive into the database. With an empty orgName every collection
+// in the archive replaces the current one; otherwise only the users, todos, history and
+// templates of that organisation are written over their current versions, with the
+// volunteers who joined its todos added if they no longer exist. Either way the data is
+// then migrated to the current schema version.
+func (m *Models) RestoreBackup(ctx context.Context, archive *backup.Archive, orgName string) (RestoreResult, error) {
+	migrator, err := m.NewMigrator()
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	if version := archive.Manifest.SchemaVersion; version > migrator.Latest() {
+		return RestoreResult{}, Unsupported("the backup is at schema version %d, newer than this build's %d", version, migrator.Latest())
+	}
+
+	// Nothing is written until the whole archive is known to be intact
+	if err := archive.Verify(); err != nil {
+		return RestoreResult{}, err
+	}
+
+	result := RestoreResult{SchemaVersion: archive.Manifest.SchemaVersion, Documents: map[string]int64{}}
+	var migrated []migrations.Migration
+	if orgName == "" {
+		if err := m.restoreAll(ctx, archive, result.Documents); err != nil {
+			return result, err
+		}
+		migrated, err = migrator.Up(ctx, 0)
+	} else {
+		if err := m.restoreOrganisation(ctx, archive, orgName, result.Documents); err != nil {
+			return result, err
+		}
+		migrated, err = migrator.Replay(ctx, archive.Manifest.SchemaVersion)
+	}
+	for _, migration := range migrated {
+		result.Migrated = append(result.Migrated, migration.Version)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Error migrating restored data", "error", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// restoreAll replaces each collection in archive with its contents, counting the documents
+// written into counts
+func (m *Models) restoreAll(ctx context.Context, archive *backup.Archive, counts map[string]int64) error {
+	db := m.client.Database(m.database)
+
+	var names []string
+	for _, c := range archive.Manifest.Collections {
+		names = append(names, c.Name)
+	}
+	// A ledger left over from the current data would claim migrations the archive lacks
+	if !archive.Has(migrations.Collection) {
+		names = append(names, migrations.Collection)
+	}
+
+	for _, name := range names {
+		collection := db.Collection(name)
+
+		deleteCtx, cancel := m.deadlines.aggregate(ctx)
+		_, err := collection.DeleteMany(deleteCtx, bson.D{})
+		cancel()
+		if err != nil {
+			logging.FromContext(ctx).Error("Error clearing collection", "collection", name, "error", err)
+			return err
+		}
+		if !archive.Has(name) {
+			continue
+		}
+
+		var batch []interface{}
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			writeCtx, cancel := m.deadlines.write(ctx)
+			defer cancel()
+			if _, err := collection.InsertMany(writeCtx, batch); err != nil {
+				return err
+			}
+			counts[name] += int64(len(batch))
+			batch = batch[:0]
+			return nil
+		}
+
+		err = archive.Documents(name, func(doc bson.D) error {
+			batch = append(batch, doc)
+			if len(batch) < restoreBatchSize {
+				return nil
+			}
+			return flush()
+		})
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("Error restoring collection", "collection", name, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreOrganisation writes the documents of the organisation called orgName in archive over
+// the current ones, counting the documents written into counts. Documents created since the
+// backup are kept.
+func (m *Models) restoreOrganisation(ctx context.Context, archive *backup.Archive, orgName string, counts map[string]int64) error {
+	db := m.client.Database(m.database)
+	replace := func(name string, doc bson.D) error {
+		writeCtx, cancel := m.deadlines.write(ctx)
+		defer cancel()
+		_, err := db.Collection(name).ReplaceOne(writeCtx, bson.M{"_id": field(doc, "_id")}, doc, options.Replace().SetUpsert(true))
+		if mongo.IsDuplicateKeyError(err) {
+			return Conflict("a %s document of the backup clashes with a current one: %v", name, err)
+		}
+		if err == nil {
+			counts[name]++
+		}
+		return err
+	}
+
+	// The organisation's own accounts come first, as their IDs pick out its todos
+	orgIDs := map[string]bool{}
+	err := documents(archive, "users", func(doc bson.D, user User) error {
+		if user.UserType != "organisation" || user.OrganisationName != orgName {
+			return nil
+		}
+		orgIDs[user.ID] = true
+		return replace("users", doc)
+	})
+	if err != nil {
+		return err
+	}
+
+	todoIDs := map[string]bool{}
+	volunteerIDs := map[string]bool{}
+	err = documents(archive, "todos", func(doc bson.D, todo Todo) error {
+		if !orgIDs[todo.OrganisationID] && (todo.OrganisationID != "" || todo.OrganisationName != orgName) {
+			return nil
+		}
+		todoIDs[todo.ID] = true
+		for _, volunteer := range todo.Volunteer {
+			volunteerIDs[volunteer.VolunteerID] = true
+		}
+		return replace("todos", doc)
+	})
+	if err != nil {
+		return err
+	}
+	if len(orgIDs) == 0 && len(todoIDs) == 0 {
+		return NotFound("organisation")
+	}
+
+	err = documents(archive, "todo_history", func(doc bson.D, entry HistoryEntry) error {
+		if !todoIDs[entry.TodoID] {
+			return nil
+		}
+		return replace("todo_history", doc)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = documents(archive, "templates", func(doc bson.D, template Template) error {
+		if !orgIDs[template.OrganisationID] && (template.OrganisationID != "" || template.OrganisationName != orgName) {
+			return nil
+		}
+		return replace("templates", doc)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Volunteers are shared with other organisations, so current accounts are left alone
+	return documents(archive, "users", func(doc bson.D, user User) error {
+		if !volunteerIDs[user.ID] || orgIDs[user.ID] {
+			return nil
+		}
+		writeCtx, cancel := m.deadlines.write(ctx)
+		defer cancel()
+		_, err := db.Collection("users").InsertOne(writeCtx, doc)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		if err == nil {
+			counts["users"]++
+		}
+		return err
+	})
+}
+
+// documents calls fn with each document of the named collection in archive along with its
+// decoded form, doing nothing if the archive doesn't hold the collection
+func documents[T any](archive *backup.Archive, name string, fn func(doc bson.D, decoded T) error) error {
+	if !archive.Has(name) {
+		return nil
+	}
+	return archive.Documents(name, func(doc bson.D) error {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		var decoded T
+		if err := bson.Unmarshal(raw, &decoded); err != nil {
+			return fmt.Errorf("%w: decoding %s document %v: %v", backup.ErrInvalid, name, field(doc, "_id"), err)
+		}
+		return fn(doc, decoded)
+	})
+}
+
+// field returns the value of key in doc, or nil
+func field(doc bson.D, key string) interface{} {
+	for _, e := range doc {
+		if e.Key == key {
+			return e.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Kinds of domain error. Check for them with errors.Is; handlers map each to an HTTP status.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrUnsupported  = errors.New("not supported")
+)
+
+// IsTimeout reports whether err comes from a database operation running out of time
+func IsTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err)
+}
+
+// IsCanceled reports whether err comes from the caller giving up, such as a client disconnecting
+func IsCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// FieldError describes a problem with a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error of a given kind with a message safe to show to clients
+type Error struct {
+	Kind   error
+	Msg    string
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Msg
+	}
+	var fields []string
+	for _, f := range e.Fields {
+		fields = append(fields, f.Field+": "+f.Message)
+	}
+	return e.Msg + " (" + strings.Join(fields, "; ") + ")"
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// NotFound returns an ErrNotFound error, e.g. NotFound("todo")
+func NotFound(resource string) error {
+	return &Error{Kind: ErrNotFound, Msg: resource + " not found"}
+}
+
+// Conflict returns an ErrConflict error
+func Conflict(format string, args ...interface{}) error {
+	return &Error{Kind: ErrConflict, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Forbidden returns an ErrForbidden error
+func Forbidden(format string, args ...interface{}) error {
+	return &Error{Kind: ErrForbidden, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Unauthorized returns an ErrUnauthorized error
+func Unauthorized(format string, args ...interface{}) error {
+	return &Error{Kind: ErrUnauthorized, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Unsupported returns an ErrUnsupported error for a feature the current setup can't provide
+func Unsupported(format string, args ...interface{}) error {
+	return &Error{Kind: ErrUnsupported, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Invalid returns an ErrValidation error for one or more fields
+func Invalid(fields ...FieldError) error {
+	return &Error{Kind: ErrValidation, Msg: "request is invalid", Fields: fields}
+}
+
+// notFoundOr turns a missing document or malformed ID into a NotFound error for resource,
+// and passes any other error through
+func notFoundOr(err error, resource string) error {
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return NotFound(resource)
+	}
+	return err
+}
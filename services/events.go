@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/volunteerService-backend/logging"
+)
+
+// eventBuffer is how many changes a subscriber may fall behind before changes are dropped for it
+const eventBuffer = 64
+
+// broker fans out every recorded todo change to the current subscribers
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[chan HistoryEntry]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{subscribers: map[chan HistoryEntry]struct{}{}}
+}
+
+// SubscribeTodoChanges returns a channel receiving every todo change as it's recorded,
+// and a function to stop the subscription. A subscriber that falls too far behind
+// misses changes rather than holding up the writers.
+func (m *Models) SubscribeTodoChanges() (<-chan HistoryEntry, func()) {
+	ch := make(chan HistoryEntry, eventBuffer)
+
+	m.events.mu.Lock()
+	m.events.subscribers[ch] = struct{}{}
+	m.events.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			m.events.mu.Lock()
+			delete(m.events.subscribers, ch)
+			m.events.mu.Unlock()
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// publish sends entry to every subscriber without blocking
+func (b *broker) publish(ctx context.Context, entry HistoryEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- entry:
+		default:
+			logging.FromContext(ctx).Warn("Dropping todo change for slow subscriber", "todoId", entry.TodoID)
+		}
+	}
+}
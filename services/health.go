@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// requiredIndexes are the indexes, created by the schema migrations, that the API relies on
+// for correctness or performance
+var requiredIndexes = []struct {
+	collection string
+	names      []string
+}{
+	{"users", []string{"email_unique"}},
+	{"todos", []string{"orgId", "orgName", "volType"}},
+	{"todo_history", []string{"todoId_timestamp"}},
+}
+
+// PingMongo checks the primary can be reached
+func (m *Models) PingMongo(ctx context.Context) error {
+	return m.client.Ping(ctx, readpref.Primary())
+}
+
+// MissingIndexes returns the required indexes that don't exist, as "collection.name"
+func (m *Models) MissingIndexes(ctx context.Context) ([]string, error) {
+	var missing []string
+	for _, required := range requiredIndexes {
+		cursor, err := m.client.Database(m.database).Collection(required.collection).Indexes().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var specs []bson.M
+		if err := cursor.All(ctx, &specs); err != nil {
+			return nil, err
+		}
+
+		existing := map[string]bool{}
+		for _, spec := range specs {
+			existing[fmt.Sprint(spec["name"])] = true
+		}
+		for _, name := range required.names {
+			if !existing[name] {
+				missing = append(missing, required.collection+"."+name)
+			}
+		}
+	}
+
+	return missing, nil
+}
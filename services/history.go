@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/volunteerService-backend/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// History actions recorded for a todo
+const (
+	HistoryCreate = "create"
+	HistoryUpdate = "update"
+	HistoryDelete = "delete"
+	HistoryRoster = "roster"
+)
+
+// FieldChange is the before and after value of a single todo field
+type FieldChange struct {
+	Field string      `json:"field" bson:"field"`
+	Old   interface{} `json:"old,omitempty" bson:"old,omitempty"`
+	New   interface{} `json:"new,omitempty" bson:"new,omitempty"`
+}
+
+// MaxHistoryPage is the last page of a todo's history that can be asked for
+const MaxHistoryPage = 10000
+
+// HistoryEntry is one append-only record of a change made to a todo. The owning organisation
+// is kept on each entry, so the history stays protected once the todo is deleted.
+type HistoryEntry struct {
+	ID               string        `json:"id,omitempty" bson:"_id,omitempty"`
+	TodoID           string        `json:"todoId" bson:"todoId"`
+	OrganisationID   string        `json:"orgId,omitempty" bson:"orgId,omitempty"`
+	OrganisationName string        `json:"orgName,omitempty" bson:"orgName,omitempty"`
+	Action           string        `json:"action" bson:"action"`
+	Actor            string        `json:"actor" bson:"actor"`
+	Timestamp        time.Time     `json:"timestamp" bson:"timestamp"`
+	Changes          []FieldChange `json:"changes" bson:"changes"`
+}
+
+// IsOwnedBy reports whether the entry belongs to the given organisation user
+func (e HistoryEntry) IsOwnedBy(user User) bool {
+	if e.OrganisationID != "" {
+		return e.OrganisationID == user.ID
+	}
+	return user.isOrganisationNamed(e.OrganisationName)
+}
+
+// todoFields flattens a todo into its JSON field names so two versions can be compared
+func todoFields(todo *Todo) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if todo == nil {
+		return fields
+	}
+
+	raw, err := json.Marshal(todo)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(raw, &fields)
+	delete(fields, "id")
+
+	return fields
+}
+
+// diffTodos returns a change for every field that differs between before and after.
+// A nil before or after records a create or delete respectively.
+func diffTodos(before, after *Todo) []FieldChange {
+	oldFields, newFields := todoFields(before), todoFields(after)
+
+	names := map[string]bool{}
+	for name := range oldFields {
+		names[name] = true
+	}
+	for name := range newFields {
+		names[name] = true
+	}
+
+	var changes []FieldChange
+	for name := range names {
+		if !reflect.DeepEqual(oldFields[name], newFields[name]) {
+			changes = append(changes, FieldChange{Field: name, Old: oldFields[name], New: newFields[name]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes
+}
+
+// recordHistory appends an entry to the history of todo, as it was stored before or after the change.
+// Failing to record history is logged but never fails the change itself.
+func (m *Models) recordHistory(ctx context.Context, todo Todo, action, actor string, changes []FieldChange) {
+	if len(changes) == 0 {
+		return
+	}
+	if actor == "" {
+		actor = "anonymous"
+	}
+
+	entry := HistoryEntry{
+		TodoID:           todo.ID,
+		OrganisationID:   todo.OrganisationID,
+		OrganisationName: todo.OrganisationName,
+		Action:           action,
+		Actor:            actor,
+		Timestamp:        time.Now(),
+		Changes:          changes,
+	}
+
+	err := m.history.Append(ctx, entry)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error recording todo history", "error", err)
+	}
+
+	m.events.publish(ctx, entry)
+}
+
+// recordTodoChange records an edit, splitting volunteer changes out as a roster entry
+func (m *Models) recordTodoChange(ctx context.Context, actor string, before, after *Todo) {
+	var fieldChanges, rosterChanges []FieldChange
+	for _, change := range diffTodos(before, after) {
+		if change.Field == "volunteer" {
+			rosterChanges = append(rosterChanges, change)
+		} else {
+			fieldChanges = append(fieldChanges, change)
+		}
+	}
+
+	m.recordHistory(ctx, *before, HistoryUpdate, actor, fieldChanges)
+	m.recordHistory(ctx, *before, HistoryRoster, actor, rosterChanges)
+}
+
+// GetTodoHistory returns a page of a todo's history, newest first, along with the total number of entries
+func (m *Models) GetTodoHistory(ctx context.Context, todoID string, page, limit int) ([]HistoryEntry, int64, error) {
+	if page > MaxHistoryPage {
+		return nil, 0, Invalid(FieldError{Field: "page", Message: fmt.Sprintf("must be at most %d", MaxHistoryPage)})
+	}
+
+	entries, total, err := m.history.List(ctx, todoID, page, limit)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding todo history", "error", err)
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// AuthorizeHistory checks that the user with email, the authenticated caller, may view the
+// history of the todo todoID. Only members of the owning organisation recorded in the history
+// may, whether or not the todo still exists.
+func (m *Models) AuthorizeHistory(ctx context.Context, email, todoID string) error {
+	if email == "" {
+		return Unauthorized("Authentication required")
+	}
+	user, err := m.users.GetByEmail(ctx, email)
+	if err != nil {
+		return Unauthorized("Authentication required")
+	}
+
+	latest, total, err := m.history.List(ctx, todoID, 1, 1)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding todo history", "error", err)
+		return err
+	}
+	if total == 0 {
+		return NotFound("todo")
+	}
+
+	if !latest[0].IsOwnedBy(user) {
+		return Forbidden("Only the owning organisation can view this history")
+	}
+
+	return nil
+}
+
+// historyOwner returns the organisation recorded in the changes of a create or delete entry
+func historyOwner(changes []FieldChange) (orgID, orgName string) {
+	for _, change := range changes {
+		value := change.New
+		if value == nil {
+			value = change.Old
+		}
+		switch text, _ := value.(string); change.Field {
+		case "orgId":
+			orgID = text
+		case "orgName":
+			orgName = text
+		}
+	}
+	return orgID, orgName
+}
+
+// backfillHistoryOwners records the owning organisation on the history entries of db written
+// before it was kept, taking it from the create or delete entry of the same todo so the history
+// of deleted todos is covered too. It returns the number of entries updated.
+func backfillHistoryOwners(ctx context.Context, db *mongo.Database, d deadlines) (int, error) {
+	collection := db.Collection("todo_history")
+	missing := bson.M{"$exists": false}
+
+	cursor, err := collection.Find(ctx, bson.M{
+		"action":  bson.M{"$in": bson.A{HistoryCreate, HistoryDelete}},
+		"orgId":   missing,
+		"orgName": missing,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding todo history to backfill", "error", err)
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var entry HistoryEntry
+		if err := cursor.Decode(&entry); err != nil {
+			logging.FromContext(ctx).Error("Error decoding history entry", "error", err)
+			continue
+		}
+
+		set := bson.M{}
+		orgID, orgName := historyOwner(entry.Changes)
+		if orgID != "" {
+			set["orgId"] = orgID
+		}
+		if orgName != "" {
+			set["orgName"] = orgName
+		}
+		if len(set) == 0 {
+			continue
+		}
+
+		writeCtx, cancel := d.write(ctx)
+		res, err := collection.UpdateMany(writeCtx, bson.M{"todoId": entry.TodoID, "orgId": missing, "orgName": missing}, bson.M{"$set": set})
+		cancel()
+		if err != nil {
+			logging.FromContext(ctx).Error("Error backfilling todo history", "error", err)
+			return updated, err
+		}
+		updated += int(res.ModifiedCount)
+	}
+
+	if err := cursor.Err(); err != nil {
+		logging.FromContext(ctx).Error("Error with cursor", "error", err)
+		return updated, err
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,27 @@
+package services
+
+import "testing"
+
+func TestHistoryOwner(t *testing.T) {
+	created := diffTodos(nil, &Todo{Task: "Sort donations", OrganisationID: "org", OrganisationName: "Helping Hands"})
+	if orgID, orgName := historyOwner(created); orgID != "org" || orgName != "Helping Hands" {
+		t.Errorf("got %q, %q from a create entry", orgID, orgName)
+	}
+
+	deleted := diffTodos(&Todo{Task: "Sort donations", OrganisationName: "Helping Hands"}, nil)
+	if orgID, orgName := historyOwner(deleted); orgID != "" || orgName != "Helping Hands" {
+		t.Errorf("got %q, %q from a delete entry", orgID, orgName)
+	}
+
+	entry := HistoryEntry{OrganisationName: "Helping Hands"}
+	if !entry.IsOwnedBy(User{ID: "org", UserType: "organisation", OrganisationName: "Helping Hands"}) || entry.IsOwnedBy(User{ID: "ada"}) {
+		t.Error("got the wrong owner for an entry with only a name")
+	}
+	if entry.IsOwnedBy(User{ID: "ada", UserType: "volunteer", OrganisationName: "Helping Hands"}) {
+		t.Error("a volunteer giving the organisation's name owns the entry")
+	}
+	entry.OrganisationID = "org"
+	if entry.IsOwnedBy(User{ID: "other", UserType: "organisation", OrganisationName: "Helping Hands"}) {
+		t.Error("an organisation reusing the name owns the entry")
+	}
+}
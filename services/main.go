@@ -1,5 +1,52 @@
 package services
 
+import (
+	"net/http"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Models are the services, holding the repositories and configuration they work with.
+// Everything is injected by New, so separate Models share no state.
 type Models struct {
-	Todo Todo
+	todos     TodoRepository
+	users     UserRepository
+	history   HistoryRepository
+	templates TemplateRepository
+
+	// client and database are nil and empty on the memory storage backend
+	client   *mongo.Client
+	database string
+	// deadlines bound the database operations made outside the repositories
+	deadlines deadlines
+
+	// jwtSecret signs the JWT tokens, which stay valid for tokenTTL
+	jwtSecret []byte
+	tokenTTL  time.Duration
+	// cookieSameSite is the SameSite attribute of the cookies set at login
+	cookieSameSite http.SameSite
+
+	// events fans out the recorded todo changes
+	events *broker
+}
+
+// New returns the services over the configured storage backend.
+// mongo may be nil when the configured storage backend is memory.
+func New(mongo *mongo.Client, cfg config.Config) *Models {
+	repos := newRepositories(mongo, cfg)
+	return &Models{
+		todos:          repos.Todos,
+		users:          repos.Users,
+		history:        repos.History,
+		templates:      repos.Templates,
+		client:         mongo,
+		database:       cfg.Mongo.Database,
+		deadlines:      deadlines(cfg.Mongo.Timeouts),
+		jwtSecret:      []byte(cfg.Security.JWTSecret),
+		tokenTTL:       cfg.Security.TokenTTL,
+		cookieSameSite: sameSiteModes[cfg.Security.CookieSameSite],
+		events:         newBroker(),
+	}
 }
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/migrations"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// schemaMigrations are the changes to the Mongo schema and data, in the order they were made,
+// with the data migrations bounded by d. Released migrations must never be edited or renumbered;
+// add a new one instead.
+func schemaMigrations(d deadlines) []migrations.Migration {
+	return []migrations.Migration{
+		{
+			Version:     1,
+			Description: "Index users by email and contact number",
+			Up: migrations.CreateIndexes("users",
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "email", Value: 1}},
+					Options: options.Index().SetName("email_unique").SetUnique(true),
+				},
+				// Only users with a contact number take part, as it used to be optional
+				mongo.IndexModel{
+					Keys: bson.D{{Key: "contactNo", Value: 1}},
+					Options: options.Index().SetName("contactNo_unique").SetUnique(true).
+						SetPartialFilterExpression(bson.M{"contactNo": bson.M{"$type": "string"}}),
+				},
+			),
+			Down: migrations.DropIndexes("users", "email_unique", "contactNo_unique"),
+		},
+		{
+			Version:     2,
+			Description: "Index todos by organisation, volunteer type and time",
+			Up: migrations.CreateIndexes("todos",
+				mongo.IndexModel{Keys: bson.D{{Key: "orgId", Value: 1}}, Options: options.Index().SetName("orgId")},
+				mongo.IndexModel{Keys: bson.D{{Key: "orgName", Value: 1}}, Options: options.Index().SetName("orgName")},
+				mongo.IndexModel{Keys: bson.D{{Key: "volType", Value: 1}}, Options: options.Index().SetName("volType")},
+				mongo.IndexModel{Keys: bson.D{{Key: "time", Value: 1}}, Options: options.Index().SetName("time")},
+			),
+			Down: migrations.DropIndexes("todos", "orgId", "orgName", "volType", "time"),
+		},
+		{
+			Version:     3,
+			Description: "Add text and geo indexes on todos",
+			Up: migrations.CreateIndexes("todos",
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "task", Value: "text"}, {Key: "description", Value: "text"}},
+					Options: options.Index().SetName("task_description_text").SetWeights(bson.M{"task": 3, "description": 1}),
+				},
+				// For todo locations stored as GeoJSON points; todos without one are left out
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "location", Value: "2dsphere"}},
+					Options: options.Index().SetName("location_2dsphere"),
+				},
+			),
+			Down: migrations.DropIndexes("todos", "task_description_text", "location_2dsphere"),
+		},
+		{
+			Version:     4,
+			Description: "Index todo history by todo and time",
+			Up: migrations.CreateIndexes("todo_history", mongo.IndexModel{
+				Keys:    bson.D{{Key: "todoId", Value: 1}, {Key: "timestamp", Value: -1}},
+				Options: options.Index().SetName("todoId_timestamp"),
+			}),
+			Down: migrations.DropIndexes("todo_history", "todoId_timestamp"),
+		},
+		{
+			Version:     5,
+			Description: "Backfill organisation and volunteer IDs on todos and templates",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				todos, templates, err := backfillReferenceIDs(ctx, db, d)
+				logging.FromContext(ctx).Info("Backfilled reference IDs", "todos", todos, "templates", templates)
+				return err
+			},
+			// The IDs are what new todos store anyway, so there is nothing to undo
+			Down: func(ctx context.Context, db *mongo.Database) error { return nil },
+		},
+		{
+			Version:     6,
+			Description: "Record the owning organisation on todo history",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				updated, err := backfillHistoryOwners(ctx, db, d)
+				logging.FromContext(ctx).Info("Backfilled todo history owners", "entries", updated)
+				return err
+			},
+			// New entries carry the owner anyway, so there is nothing to undo
+			Down: func(ctx context.Context, db *mongo.Database) error { return nil },
+		},
+		{
+			Version:     7,
+			Description: "Drop the unused geo index on todos",
+			// Todos have never stored a location, so nothing queries the index
+			Up: migrations.DropIndexes("todos", "location_2dsphere"),
+			Down: migrations.CreateIndexes("todos", mongo.IndexModel{
+				Keys:    bson.D{{Key: "location", Value: "2dsphere"}},
+				Options: options.Index().SetName("location_2dsphere"),
+			}),
+		},
+	}
+}
+
+// NewMigrator returns a Migrator for the schema migrations of the Mongo database
+func (m *Models) NewMigrator() (*migrations.Migrator, error) {
+	if err := m.requireMongo("migrations"); err != nil {
+		return nil, err
+	}
+	return migrations.New(m.client.Database(m.database), schemaMigrations(m.deadlines))
+}
+
+// PendingMigrations returns the versions of the schema migrations not applied yet
+func (m *Models) PendingMigrations(ctx context.Context) ([]int, error) {
+	migrator, err := m.NewMigrator()
+	if err != nil {
+		return nil, err
+	}
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []int
+	for _, migration := range pending {
+		versions = append(versions, migration.Version)
+	}
+	return versions, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/volunteerService-backend/logging"
+)
+
+// Organisation is the public profile of an organisation user
+type Organisation struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type,omitempty"`
+	Email         string `json:"email,omitempty"`
+	ContactNumber string `json:"contactNo,omitempty"`
+}
+
+// OrganisationProfile returns the public profile of an organisation user
+func (u User) OrganisationProfile() Organisation {
+	return Organisation{
+		ID:            u.ID,
+		Name:          u.OrganisationName,
+		Type:          u.OrganisationType,
+		Email:         u.Email,
+		ContactNumber: u.ContactNumber,
+	}
+}
+
+// GetOrganisations returns every registered organisation
+func (m *Models) GetOrganisations(ctx context.Context) ([]Organisation, error) {
+	users, err := m.users.ListByType(ctx, "organisation")
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding organisations", "error", err)
+		return nil, err
+	}
+
+	organisations := make([]Organisation, 0, len(users))
+	for _, user := range users {
+		organisations = append(organisations, user.OrganisationProfile())
+	}
+
+	return organisations, nil
+}
+
+// AuthorizeOrganisation checks that the user with email, the authenticated caller, is an
+// organisation user called orgName, and returns that organisation. Names aren't unique, so
+// its private data must then be looked up by the ID returned, not by orgName.
+func (m *Models) AuthorizeOrganisation(ctx context.Context, email, orgName string) (Organisation, error) {
+	if email == "" {
+		return Organisation{}, Unauthorized("Authentication required")
+	}
+	user, err := m.users.GetByEmail(ctx, email)
+	if err != nil {
+		return Organisation{}, Unauthorized("Authentication required")
+	}
+
+	if user.UserType != "organisation" || user.OrganisationName != orgName {
+		return Organisation{}, Forbidden("Only members of the organisation can see this")
+	}
+
+	return user.OrganisationProfile(), nil
+}
+
+// GetOrganisationById returns a single organisation, or NotFound if the user isn't an organisation
+func (m *Models) GetOrganisationById(ctx context.Context, id string) (Organisation, error) {
+	user, err := m.users.Get(ctx, id)
+	if errors.Is(err, ErrNotFound) || (err == nil && user.UserType != "organisation") {
+		return Organisation{}, NotFound("organisation")
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding organisation", "error", err)
+		return Organisation{}, err
+	}
+
+	return user.OrganisationProfile(), nil
+}
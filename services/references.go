@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/volunteerService-backend/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DisplayName returns the full name of the user
+func (u User) DisplayName() string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+// isOrganisationNamed reports whether the user is an organisation going by orgName.
+// Volunteers can carry an organisation name too, but never stand for one.
+func (u User) isOrganisationNamed(orgName string) bool {
+	return u.UserType == "organisation" && orgName != "" && u.OrganisationName == orgName
+}
+
+// resolveNames fills in OrganisationName and VolunteerName from the referenced users,
+// so todos always show current names even if the stored copies have gone stale.
+// Users are fetched in a single batched query for all the todos.
+func (m *Models) resolveNames(ctx context.Context, todos []Todo) error {
+	seen := map[string]bool{}
+	var ids []string
+	addID := func(id string) {
+		if seen[id] || !primitive.IsValidObjectID(id) {
+			return
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	for _, todo := range todos {
+		if todo.OrganisationID != "" {
+			addID(todo.OrganisationID)
+		}
+		for _, volunteer := range todo.Volunteer {
+			addID(volunteer.VolunteerID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	users, err := m.GetUsersByID(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byID := map[string]User{}
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	for i := range todos {
+		if org, ok := byID[todos[i].OrganisationID]; ok && org.OrganisationName != "" {
+			todos[i].OrganisationName = org.OrganisationName
+		}
+		for j := range todos[i].Volunteer {
+			if volunteer, ok := byID[todos[i].Volunteer[j].VolunteerID]; ok && volunteer.DisplayName() != "" {
+				todos[i].Volunteer[j].VolunteerName = volunteer.DisplayName()
+			}
+		}
+	}
+
+	return nil
+}
+
+// findOrganisationIDs returns the IDs of the organisation users currently using orgName
+func (m *Models) findOrganisationIDs(ctx context.Context, orgName string) ([]string, error) {
+	users, err := m.users.ListByOrganisationName(ctx, orgName)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding organisations by orgName", "error", err)
+		return nil, err
+	}
+
+	var ids []string
+	for _, user := range users {
+		if user.isOrganisationNamed(orgName) {
+			ids = append(ids, user.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// organisationMatch matches todos or templates referencing any of orgIDs, or carrying only orgName
+func organisationMatch(orgIDs []string, orgName string) bson.M {
+	if len(orgIDs) == 0 {
+		return bson.M{"orgName": orgName}
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{"orgId": bson.M{"$in": orgIDs}},
+		bson.M{"orgId": bson.M{"$exists": false}, "orgName": orgName},
+	}}
+}
+
+// referenceIndex resolves organisation and volunteer names to the IDs of the users holding them.
+// Names shared by more than one user can't be resolved, so they are left alone.
+type referenceIndex struct {
+	orgIDs       map[string][]string
+	volunteerIDs map[string][]string
+}
+
+func newReferenceIndex(users []User) referenceIndex {
+	index := referenceIndex{orgIDs: map[string][]string{}, volunteerIDs: map[string][]string{}}
+	for _, user := range users {
+		if user.isOrganisationNamed(user.OrganisationName) {
+			index.orgIDs[user.OrganisationName] = append(index.orgIDs[user.OrganisationName], user.ID)
+		}
+		if user.DisplayName() != "" {
+			index.volunteerIDs[user.DisplayName()] = append(index.volunteerIDs[user.DisplayName()], user.ID)
+		}
+	}
+	return index
+}
+
+// resolve returns the only ID in ids, or "" when there is none or several
+func resolve(ids []string) string {
+	if len(ids) != 1 {
+		return ""
+	}
+	return ids[0]
+}
+
+// todoUpdate fills in the IDs todo is missing where the names resolve, returning the fields
+// to set, or nil when nothing changed
+func (index referenceIndex) todoUpdate(todo *Todo) bson.M {
+	set := bson.M{}
+	if id := resolve(index.orgIDs[todo.OrganisationName]); todo.OrganisationID == "" && id != "" {
+		todo.OrganisationID = id
+		set["orgId"] = id
+	}
+	for i, volunteer := range todo.Volunteer {
+		if id := resolve(index.volunteerIDs[volunteer.VolunteerName]); volunteer.VolunteerID == "" && id != "" {
+			todo.Volunteer[i].VolunteerID = id
+			set["volunteer"] = todo.Volunteer
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	return set
+}
+
+// templateUpdate fills in the organisation ID of template where its name resolves, returning
+// the fields to set, or nil when nothing changed
+func (index referenceIndex) templateUpdate(template *Template) bson.M {
+	id := resolve(index.orgIDs[template.OrganisationName])
+	if template.OrganisationID != "" || id == "" {
+		return nil
+	}
+	template.OrganisationID = id
+	return bson.M{"orgId": id}
+}
+
+// backfillReferenceIDs sets the organisation ID on the todos and templates of db that only have
+// an organisation name, and VolunteerID on volunteers that only have a VolunteerName, where the
+// match is unambiguous. It returns the number of todos and templates that were updated.
+func backfillReferenceIDs(ctx context.Context, db *mongo.Database, d deadlines) (todos, templates int, err error) {
+	// Loading every user is one scan; the documents are then streamed and updated one by one
+	scanCtx, cancel := d.aggregate(ctx)
+	defer cancel()
+	var users []User
+	if err := findAll(scanCtx, db.Collection("users"), bson.D{}, &users); err != nil {
+		return 0, 0, err
+	}
+	index := newReferenceIndex(users)
+
+	todos, err = backfillDocuments(ctx, db.Collection("todos"), d, bson.M{"$or": bson.A{
+		bson.M{"orgId": bson.M{"$exists": false}},
+		bson.M{"volunteer": bson.M{"$elemMatch": bson.M{"volunteerId": bson.M{"$exists": false}}}},
+	}}, func(todo *Todo) (string, bson.M) {
+		return todo.ID, index.todoUpdate(todo)
+	})
+	if err != nil {
+		return todos, 0, err
+	}
+
+	templates, err = backfillDocuments(ctx, db.Collection("templates"), d, bson.M{"orgId": bson.M{"$exists": false}},
+		func(template *Template) (string, bson.M) {
+			return template.ID, index.templateUpdate(template)
+		})
+	return todos, templates, err
+}
+
+// backfillDocuments streams the documents of collection matching filter, setting on each the
+// fields update returns for it, if any. It returns the number of documents updated.
+func backfillDocuments[T any](ctx context.Context, collection *mongo.Collection, d deadlines, filter bson.M, update func(doc *T) (string, bson.M)) (int, error) {
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding documents to backfill", "collection", collection.Name(), "error", err)
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var doc T
+		if err := cursor.Decode(&doc); err != nil {
+			logging.FromContext(ctx).Error("Error decoding document", "collection", collection.Name(), "error", err)
+			continue
+		}
+
+		id, set := update(&doc)
+		if set == nil {
+			continue
+		}
+		mongoID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		writeCtx, cancel := d.write(ctx)
+		_, err = collection.UpdateOne(writeCtx, bson.M{"_id": mongoID}, bson.M{"$set": set})
+		cancel()
+		if err != nil {
+			logging.FromContext(ctx).Error("Error backfilling document", "collection", collection.Name(), "error", err)
+			return updated, err
+		}
+		updated++
+	}
+
+	if err := cursor.Err(); err != nil {
+		logging.FromContext(ctx).Error("Error with cursor", "error", err)
+		return updated, err
+	}
+
+	return updated, nil
+}
+
+// organisationIDFor returns the ID of the organisation called orgName that something created
+// by actor belongs to: the actor's own account when it is that organisation, otherwise the
+// single organisation using the name, if there is one. References by ID mean renaming an
+// organisation doesn't orphan its todos and templates.
+func (m *Models) organisationIDFor(ctx context.Context, orgName, actor string) string {
+	if orgName == "" {
+		return ""
+	}
+	if actor != "" {
+		user, err := m.users.GetByEmail(ctx, actor)
+		if err == nil && user.isOrganisationNamed(orgName) {
+			return user.ID
+		}
+	}
+
+	ids, err := m.findOrganisationIDs(ctx, orgName)
+	if err != nil || len(ids) != 1 {
+		return ""
+	}
+	return ids[0]
+}
+
+// IsOwnedBy reports whether the todo belongs to the given organisation user
+func (t Todo) IsOwnedBy(user User) bool {
+	if t.OrganisationID != "" {
+		return t.OrganisationID == user.ID
+	}
+	return user.isOrganisationNamed(t.OrganisationName)
+}
+
+// IsOwnedBy reports whether the template belongs to the given organisation user
+func (t Template) IsOwnedBy(user User) bool {
+	if t.OrganisationID != "" {
+		return t.OrganisationID == user.ID
+	}
+	return user.isOrganisationNamed(t.OrganisationName)
+}
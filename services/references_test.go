@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReferenceIndex(t *testing.T) {
+	index := newReferenceIndex([]User{
+		{ID: "org", UserType: "organisation", OrganisationName: "Helping Hands"},
+		{ID: "ada", FirstName: "Ada", LastName: "Lovelace"},
+		{ID: "grace1", FirstName: "Grace", LastName: "Hopper"},
+		{ID: "grace2", FirstName: "Grace", LastName: "Hopper"},
+		// A volunteer naming the organisation they help doesn't make the name ambiguous
+		{ID: "alan", UserType: "volunteer", FirstName: "Alan", LastName: "Turing", OrganisationName: "Helping Hands"},
+		{ID: "edsger", UserType: "volunteer", FirstName: "Edsger", LastName: "Dijkstra", OrganisationName: "Food Bank"},
+	})
+
+	todo := Todo{
+		OrganisationName: "Helping Hands",
+		Volunteer:        []Volunteer{{VolunteerName: "Ada Lovelace"}, {VolunteerName: "Grace Hopper"}},
+	}
+	set := index.todoUpdate(&todo)
+	if set["orgId"] != "org" || todo.OrganisationID != "org" {
+		t.Errorf("got %v, want the organisation ID set", set)
+	}
+	// The shared name stays unresolved
+	if todo.Volunteer[0].VolunteerID != "ada" || todo.Volunteer[1].VolunteerID != "" || set["volunteer"] == nil {
+		t.Errorf("got volunteers %+v", todo.Volunteer)
+	}
+
+	// IDs already there are never replaced
+	resolved := Todo{OrganisationID: "other", OrganisationName: "Helping Hands", Volunteer: []Volunteer{{VolunteerID: "x", VolunteerName: "Ada Lovelace"}}}
+	if set := index.todoUpdate(&resolved); set != nil {
+		t.Errorf("got %v for a todo with every ID", set)
+	}
+	if set := index.todoUpdate(&Todo{OrganisationName: "Food Bank"}); set != nil {
+		t.Errorf("got %v for an organisation only a volunteer names", set)
+	}
+
+	template := Template{OrganisationName: "Helping Hands"}
+	if set := index.templateUpdate(&template); set["orgId"] != "org" || template.OrganisationID != "org" {
+		t.Errorf("got %v, want the template's organisation ID set", set)
+	}
+	if set := index.templateUpdate(&template); set != nil {
+		t.Errorf("got %v backfilling the template again", set)
+	}
+}
+
+func TestFindOrganisationIDs(t *testing.T) {
+	ctx := context.Background()
+	m := &Models{users: NewMemoryRepositories().Users}
+	org, err := m.users.Insert(ctx, User{UserType: "organisation", Email: "org@example.com", OrganisationName: "Helping Hands"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.users.Insert(ctx, User{UserType: "volunteer", Email: "ada@example.com", OrganisationName: "Helping Hands"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids, err := m.findOrganisationIDs(ctx, "Helping Hands"); err != nil || len(ids) != 1 || ids[0] != org {
+		t.Errorf("got %v, %v, want only the organisation %s", ids, err, org)
+	}
+	if id := m.organisationIDFor(ctx, "Helping Hands", "ada@example.com"); id != org {
+		t.Errorf("got %q for a todo a volunteer creates, want the organisation %s", id, org)
+	}
+}
+
+func TestIsOwnedBy(t *testing.T) {
+	org := User{ID: "org", UserType: "organisation", OrganisationName: "Helping Hands"}
+	volunteer := User{ID: "ada", UserType: "volunteer", OrganisationName: "Helping Hands"}
+
+	// Only documents without an organisation ID fall back to the name
+	legacy := Todo{OrganisationName: "Helping Hands"}
+	if !legacy.IsOwnedBy(org) || legacy.IsOwnedBy(volunteer) {
+		t.Error("got the wrong owner for a todo with only a name")
+	}
+	template := Template{OrganisationName: "Helping Hands"}
+	if !template.IsOwnedBy(org) || template.IsOwnedBy(volunteer) {
+		t.Error("got the wrong owner for a template with only a name")
+	}
+	if (Todo{}).IsOwnedBy(User{UserType: "organisation"}) {
+		t.Error("an organisation without a name owns a todo without one")
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TodoRepository stores todos. Lookups by ID return NotFound("todo") for missing or malformed IDs.
+type TodoRepository interface {
+	List(ctx context.Context) ([]Todo, error)
+	Get(ctx context.Context, id string) (Todo, error)
+	// ListByOrganisation returns todos referencing any of orgIDs, and todos without an
+	// organisation ID whose stored name is orgName
+	ListByOrganisation(ctx context.Context, orgIDs []string, orgName string) ([]Todo, error)
+	ListByVolunteerType(ctx context.Context, volType string) ([]Todo, error)
+	// ListOverlapping returns the todos joined by volunteerID that overlap start to end,
+	// except the todo excludeID
+	ListOverlapping(ctx context.Context, volunteerID string, start, end time.Time, excludeID string) ([]Todo, error)
+	Insert(ctx context.Context, todo Todo) (string, error)
+	// Update sets the task and completion of a todo and appends entry's volunteers
+	Update(ctx context.Context, id string, entry Todo) error
+	// RemoveVolunteer takes volunteerID off the todo, returning NotFound("volunteer") if it wasn't on it
+	RemoveVolunteer(ctx context.Context, id, volunteerID string) error
+	Delete(ctx context.Context, id string) error
+}
+
+// UserRepository stores users. Lookups return NotFound("user") when nothing matches.
+type UserRepository interface {
+	Get(ctx context.Context, id string) (User, error)
+	GetByEmail(ctx context.Context, email string) (User, error)
+	GetByContactNumber(ctx context.Context, contactNo string) (User, error)
+	// ListByIDs returns the users that exist among ids, which must be valid ObjectID hex strings
+	ListByIDs(ctx context.Context, ids []string) ([]User, error)
+	ListByOrganisationName(ctx context.Context, orgName string) ([]User, error)
+	ListByType(ctx context.Context, userType string) ([]User, error)
+	Insert(ctx context.Context, user User) (string, error)
+	SetAvailability(ctx context.Context, id string, availability Availability) error
+	SetRole(ctx context.Context, id, role string) error
+	// SetPassword replaces the stored password hash
+	SetPassword(ctx context.Context, id, hash string) error
+	// SetDeactivatedAt deactivates the user as of at, or reactivates them when at is nil
+	SetDeactivatedAt(ctx context.Context, id string, at *time.Time) error
+}
+
+// HistoryRepository stores the append-only change history of todos
+type HistoryRepository interface {
+	Append(ctx context.Context, entry HistoryEntry) error
+	// List returns a page of a todo's history, newest first, and the total number of entries
+	List(ctx context.Context, todoID string, page, limit int) ([]HistoryEntry, int64, error)
+}
+
+// TemplateRepository stores todo templates. Lookups by ID return NotFound("template").
+type TemplateRepository interface {
+	ListByOrganisation(ctx context.Context, orgIDs []string, orgName string) ([]Template, error)
+	Get(ctx context.Context, id string) (Template, error)
+	Insert(ctx context.Context, template Template) (string, error)
+	// Update replaces the default values of a template, keeping its organisation
+	Update(ctx context.Context, id string, entry Template) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Repositories are the stores the services read and write
+type Repositories struct {
+	Todos     TodoRepository
+	Users     UserRepository
+	History   HistoryRepository
+	Templates TemplateRepository
+}
+
+// newRepositories returns the repositories of the configured storage backend.
+// mongo is only used, and may only be nil, when the backend is not mongo.
+func newRepositories(mongo *mongo.Client, cfg config.Config) Repositories {
+	if cfg.Storage.Backend == config.StorageMemory {
+		return NewMemoryRepositories()
+	}
+	return NewMongoRepositories(mongo, cfg.Mongo.Database, cfg.Mongo.Timeouts)
+}
+
+// UsesMongo reports whether the services are backed by MongoDB rather than memory
+func (m *Models) UsesMongo() bool {
+	return m.client != nil
+}
+
+// requireMongo fails features that are only implemented on top of MongoDB, such as migrations
+func (m *Models) requireMongo(feature string) error {
+	if !m.UsesMongo() {
+		return Unsupported("%s requires the mongo storage backend", feature)
+	}
+	return nil
+}
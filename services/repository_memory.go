@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewMemoryRepositories returns empty, thread-safe repositories that keep everything in memory.
+// They are meant for local development and tests; nothing survives a restart.
+func NewMemoryRepositories() Repositories {
+	return Repositories{
+		Todos:     &memoryTodoRepository{todos: map[string]Todo{}},
+		Users:     &memoryUserRepository{users: map[string]User{}},
+		History:   &memoryHistoryRepository{},
+		Templates: &memoryTemplateRepository{templates: map[string]Template{}},
+	}
+}
+
+// newMemoryID returns an ID in the same format Mongo would assign
+func newMemoryID() string {
+	return primitive.NewObjectID().Hex()
+}
+
+// copyTodo returns a copy of todo that shares no memory with it
+func copyTodo(todo Todo) Todo {
+	todo.Volunteer = append([]Volunteer(nil), todo.Volunteer...)
+	return todo
+}
+
+// copyUser returns a copy of user that shares no memory with it
+func copyUser(user User) User {
+	if user.Availability != nil {
+		availability := *user.Availability
+		availability.Windows = append([]AvailabilityWindow(nil), availability.Windows...)
+		availability.Blackouts = append([]string(nil), availability.Blackouts...)
+		user.Availability = &availability
+	}
+	if user.DeactivatedAt != nil {
+		at := *user.DeactivatedAt
+		user.DeactivatedAt = &at
+	}
+	return user
+}
+
+type memoryTodoRepository struct {
+	mu    sync.RWMutex
+	todos map[string]Todo
+}
+
+// filter returns a copy of every todo matching keep, oldest first
+func (m *memoryTodoRepository) filter(keep func(Todo) bool) []Todo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	todos := []Todo{}
+	for _, todo := range m.todos {
+		if keep(todo) {
+			todos = append(todos, copyTodo(todo))
+		}
+	}
+	// IDs are ObjectIDs, which sort by creation time
+	sort.Slice(todos, func(i, j int) bool { return todos[i].ID < todos[j].ID })
+
+	return todos
+}
+
+func (m *memoryTodoRepository) List(ctx context.Context) ([]Todo, error) {
+	return m.filter(func(Todo) bool { return true }), nil
+}
+
+func (m *memoryTodoRepository) Get(ctx context.Context, id string) (Todo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	todo, ok := m.todos[id]
+	if !ok {
+		return Todo{}, NotFound("todo")
+	}
+	return copyTodo(todo), nil
+}
+
+func (m *memoryTodoRepository) ListByOrganisation(ctx context.Context, orgIDs []string, orgName string) ([]Todo, error) {
+	ids := map[string]bool{}
+	for _, id := range orgIDs {
+		ids[id] = true
+	}
+
+	return m.filter(func(todo Todo) bool {
+		if len(ids) == 0 {
+			return todo.OrganisationName == orgName
+		}
+		return ids[todo.OrganisationID] || (todo.OrganisationID == "" && todo.OrganisationName == orgName)
+	}), nil
+}
+
+func (m *memoryTodoRepository) ListByVolunteerType(ctx context.Context, volType string) ([]Todo, error) {
+	return m.filter(func(todo Todo) bool { return todo.VolunteerType == volType }), nil
+}
+
+func (m *memoryTodoRepository) ListOverlapping(ctx context.Context, volunteerID string, start, end time.Time, excludeID string) ([]Todo, error) {
+	return m.filter(func(todo Todo) bool {
+		if todo.ID == excludeID || !todo.Time.Before(end) || !todo.End().After(start) {
+			return false
+		}
+		for _, volunteer := range todo.Volunteer {
+			if volunteer.VolunteerID == volunteerID {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (m *memoryTodoRepository) Insert(ctx context.Context, todo Todo) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	todo.ID = newMemoryID()
+	m.todos[todo.ID] = copyTodo(todo)
+	return todo.ID, nil
+}
+
+func (m *memoryTodoRepository) Update(ctx context.Context, id string, entry Todo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	todo, ok := m.todos[id]
+	if !ok {
+		return NotFound("todo")
+	}
+	todo.Task = entry.Task
+	todo.Completed = entry.Completed
+	todo.Volunteer = append(append([]Volunteer(nil), todo.Volunteer...), entry.Volunteer...)
+	m.todos[id] = todo
+
+	return nil
+}
+
+func (m *memoryTodoRepository) RemoveVolunteer(ctx context.Context, id, volunteerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	todo, ok := m.todos[id]
+	if !ok {
+		return NotFound("todo")
+	}
+
+	var kept []Volunteer
+	for _, volunteer := range todo.Volunteer {
+		if volunteer.VolunteerID != volunteerID {
+			kept = append(kept, volunteer)
+		}
+	}
+	if len(kept) == len(todo.Volunteer) {
+		return NotFound("volunteer")
+	}
+	todo.Volunteer = kept
+	m.todos[id] = todo
+
+	return nil
+}
+
+func (m *memoryTodoRepository) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.todos[id]; !ok {
+		return NotFound("todo")
+	}
+	delete(m.todos, id)
+
+	return nil
+}
+
+type memoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]User
+}
+
+// find returns a copy of the first user matching keep
+func (m *memoryUserRepository) find(keep func(User) bool) (User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, user := range m.users {
+		if keep(user) {
+			return copyUser(user), nil
+		}
+	}
+	return User{}, NotFound("user")
+}
+
+func (m *memoryUserRepository) Get(ctx context.Context, id string) (User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user, ok := m.users[id]
+	if !ok {
+		return User{}, NotFound("user")
+	}
+	return copyUser(user), nil
+}
+
+func (m *memoryUserRepository) GetByEmail(ctx context.Context, email string) (User, error) {
+	return m.find(func(user User) bool { return user.Email == email })
+}
+
+func (m *memoryUserRepository) GetByContactNumber(ctx context.Context, contactNo string) (User, error) {
+	return m.find(func(user User) bool { return user.ContactNumber == contactNo })
+}
+
+func (m *memoryUserRepository) ListByIDs(ctx context.Context, ids []string) ([]User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	users := []User{}
+	for _, id := range ids {
+		if user, ok := m.users[id]; ok {
+			users = append(users, copyUser(user))
+		}
+	}
+	return users, nil
+}
+
+func (m *memoryUserRepository) ListByOrganisationName(ctx context.Context, orgName string) ([]User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	users := []User{}
+	for _, user := range m.users {
+		if user.OrganisationName == orgName {
+			users = append(users, copyUser(user))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	return users, nil
+}
+
+func (m *memoryUserRepository) ListByType(ctx context.Context, userType string) ([]User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	users := []User{}
+	for _, user := range m.users {
+		if user.UserType == userType {
+			users = append(users, copyUser(user))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	return users, nil
+}
+
+func (m *memoryUserRepository) Insert(ctx context.Context, user User) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Mirror the unique indexes on email and contact number
+	for _, existing := range m.users {
+		if existing.Email == user.Email {
+			return "", Conflict("email already exists")
+		}
+		if user.ContactNumber != "" && existing.ContactNumber == user.ContactNumber {
+			return "", Conflict("contact number already exists")
+		}
+	}
+
+	user.ID = newMemoryID()
+	m.users[user.ID] = copyUser(user)
+	return user.ID, nil
+}
+
+// update applies fn to a copy of the user id and stores it
+func (m *memoryUserRepository) update(id string, fn func(*User)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[id]
+	if !ok {
+		return NotFound("user")
+	}
+	fn(&user)
+	m.users[id] = copyUser(user)
+
+	return nil
+}
+
+func (m *memoryUserRepository) SetAvailability(ctx context.Context, id string, availability Availability) error {
+	return m.update(id, func(user *User) { user.Availability = &availability })
+}
+
+func (m *memoryUserRepository) SetRole(ctx context.Context, id, role string) error {
+	return m.update(id, func(user *User) { user.Role = role })
+}
+
+func (m *memoryUserRepository) SetPassword(ctx context.Context, id, hash string) error {
+	return m.update(id, func(user *User) { user.Password = hash })
+}
+
+func (m *memoryUserRepository) SetDeactivatedAt(ctx context.Context, id string, at *time.Time) error {
+	return m.update(id, func(user *User) { user.DeactivatedAt = at })
+}
+
+type memoryHistoryRepository struct {
+	mu      sync.RWMutex
+	entries []HistoryEntry
+}
+
+func (m *memoryHistoryRepository) Append(ctx context.Context, entry HistoryEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry.ID = newMemoryID()
+	entry.Changes = append([]FieldChange(nil), entry.Changes...)
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *memoryHistoryRepository) List(ctx context.Context, todoID string, page, limit int) ([]HistoryEntry, int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Entries are appended in order, so walking backwards gives newest first
+	var matching []HistoryEntry
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if m.entries[i].TodoID == todoID {
+			matching = append(matching, m.entries[i])
+		}
+	}
+
+	entries := []HistoryEntry{}
+	for i := (page - 1) * limit; i < len(matching) && len(entries) < limit; i++ {
+		entries = append(entries, matching[i])
+	}
+
+	return entries, int64(len(matching)), nil
+}
+
+type memoryTemplateRepository struct {
+	mu        sync.RWMutex
+	templates map[string]Template
+}
+
+func (m *memoryTemplateRepository) ListByOrganisation(ctx context.Context, orgIDs []string, orgName string) ([]Template, error) {
+	ids := map[string]bool{}
+	for _, id := range orgIDs {
+		ids[id] = true
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	templates := []Template{}
+	for _, template := range m.templates {
+		if ids[template.OrganisationID] || (template.OrganisationID == "" && template.OrganisationName == orgName) {
+			templates = append(templates, template)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+	return templates, nil
+}
+
+func (m *memoryTemplateRepository) Get(ctx context.Context, id string) (Template, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	template, ok := m.templates[id]
+	if !ok {
+		return Template{}, NotFound("template")
+	}
+	return template, nil
+}
+
+func (m *memoryTemplateRepository) Insert(ctx context.Context, template Template) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	template.ID = newMemoryID()
+	m.templates[template.ID] = template
+	return template.ID, nil
+}
+
+func (m *memoryTemplateRepository) Update(ctx context.Context, id string, entry Template) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	template, ok := m.templates[id]
+	if !ok {
+		return NotFound("template")
+	}
+	m.templates[id] = template.merge(entry)
+
+	return nil
+}
+
+func (m *memoryTemplateRepository) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.templates[id]; !ok {
+		return NotFound("template")
+	}
+	delete(m.templates, id)
+
+	return nil
+}
@@ -0,0 +1,489 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewMongoRepositories returns repositories backed by the collections of a MongoDB database,
+// bounding every operation by timeouts
+func NewMongoRepositories(client *mongo.Client, database string, timeouts config.MongoTimeouts) Repositories {
+	db := client.Database(database)
+	d := deadlines(timeouts)
+	return Repositories{
+		Todos:     mongoTodoRepository{db.Collection("todos"), d},
+		Users:     mongoUserRepository{db.Collection("users"), d},
+		History:   mongoHistoryRepository{db.Collection("todo_history"), d},
+		Templates: mongoTemplateRepository{db.Collection("templates"), d},
+	}
+}
+
+// deadlines bound each kind of database operation, so a slow database fails requests
+// instead of hanging them. The caller's own deadline or cancellation still applies.
+type deadlines config.MongoTimeouts
+
+func (d deadlines) read(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, d.Read)
+}
+
+func (d deadlines) write(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, d.Write)
+}
+
+func (d deadlines) aggregate(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, d.Aggregate)
+}
+
+// findAll runs a query and decodes every matching document into out
+func findAll(ctx context.Context, collection *mongo.Collection, filter interface{}, out interface{}, opts ...*options.FindOptions) error {
+	cursor, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding documents", "collection", collection.Name(), "error", err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, out); err != nil {
+		logging.FromContext(ctx).Error("Error decoding documents", "collection", collection.Name(), "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// insertedID returns the hex ID of a newly inserted document
+func insertedID(res *mongo.InsertOneResult) string {
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		return oid.Hex()
+	}
+	id, _ := res.InsertedID.(string)
+	return id
+}
+
+type mongoTodoRepository struct {
+	collection *mongo.Collection
+	deadlines
+}
+
+func (m mongoTodoRepository) List(ctx context.Context) ([]Todo, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	todos := []Todo{}
+	err := findAll(ctx, m.collection, bson.D{}, &todos)
+	return todos, err
+}
+
+func (m mongoTodoRepository) Get(ctx context.Context, id string) (Todo, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Todo{}, NotFound("todo")
+	}
+
+	var todo Todo
+	err = m.collection.FindOne(ctx, bson.M{"_id": mongoID}).Decode(&todo)
+	if err != nil {
+		return Todo{}, notFoundOr(err, "todo")
+	}
+
+	return todo, nil
+}
+
+func (m mongoTodoRepository) ListByOrganisation(ctx context.Context, orgIDs []string, orgName string) ([]Todo, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	todos := []Todo{}
+	err := findAll(ctx, m.collection, organisationMatch(orgIDs, orgName), &todos)
+	return todos, err
+}
+
+func (m mongoTodoRepository) ListByVolunteerType(ctx context.Context, volType string) ([]Todo, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	todos := []Todo{}
+	err := findAll(ctx, m.collection, bson.M{"volType": volType}, &todos)
+	return todos, err
+}
+
+func (m mongoTodoRepository) ListOverlapping(ctx context.Context, volunteerID string, start, end time.Time, excludeID string) ([]Todo, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	filter := bson.M{
+		"volunteer.volunteerId": volunteerID,
+		"time":                  bson.M{"$lt": end},
+		"$or": bson.A{
+			bson.M{"endTime": bson.M{"$gt": start}},
+			bson.M{"endTime": bson.M{"$exists": false}, "time": bson.M{"$gt": start.Add(-defaultTaskDuration)}},
+		},
+	}
+	if mongoID, err := primitive.ObjectIDFromHex(excludeID); err == nil {
+		filter["_id"] = bson.M{"$ne": mongoID}
+	}
+
+	todos := []Todo{}
+	err := findAll(ctx, m.collection, filter, &todos)
+	return todos, err
+}
+
+func (m mongoTodoRepository) Insert(ctx context.Context, todo Todo) (string, error) {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	res, err := m.collection.InsertOne(ctx, todo)
+	if err != nil {
+		return "", err
+	}
+	return insertedID(res), nil
+}
+
+func (m mongoTodoRepository) Update(ctx context.Context, id string, entry Todo) error {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return NotFound("todo")
+	}
+
+	res, err := m.collection.UpdateOne(ctx, bson.M{"_id": mongoID}, todoUpdate(entry))
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return NotFound("todo")
+	}
+
+	return nil
+}
+
+// todoUpdate is the update document of TodoRepository.Update. Volunteers are only pushed
+// when there are some, as MongoDB rejects $each with a null array.
+func todoUpdate(entry Todo) bson.D {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "task", Value: entry.Task},
+			{Key: "completed", Value: entry.Completed},
+		}},
+	}
+	if len(entry.Volunteer) > 0 {
+		update = append(update, bson.E{Key: "$push", Value: bson.D{
+			{Key: "volunteer", Value: bson.M{"$each": entry.Volunteer}}, // Append the new volunteers to the array
+		}})
+	}
+	return update
+}
+
+func (m mongoTodoRepository) RemoveVolunteer(ctx context.Context, id, volunteerID string) error {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return NotFound("todo")
+	}
+
+	update := bson.M{"$pull": bson.M{"volunteer": bson.M{"volunteerId": volunteerID}}}
+	res, err := m.collection.UpdateOne(ctx, bson.M{"_id": mongoID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return NotFound("todo")
+	}
+	if res.ModifiedCount == 0 {
+		return NotFound("volunteer")
+	}
+
+	return nil
+}
+
+func (m mongoTodoRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return NotFound("todo")
+	}
+
+	res, err := m.collection.DeleteOne(ctx, bson.M{"_id": mongoID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return NotFound("todo")
+	}
+
+	return nil
+}
+
+type mongoUserRepository struct {
+	collection *mongo.Collection
+	deadlines
+}
+
+// findOne returns the single user matching filter
+func (m mongoUserRepository) findOne(ctx context.Context, filter bson.M) (User, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	var user User
+	err := m.collection.FindOne(ctx, filter).Decode(&user)
+	if err != nil {
+		return User{}, notFoundOr(err, "user")
+	}
+	return user, nil
+}
+
+func (m mongoUserRepository) Get(ctx context.Context, id string) (User, error) {
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return User{}, NotFound("user")
+	}
+	return m.findOne(ctx, bson.M{"_id": mongoID})
+}
+
+func (m mongoUserRepository) GetByEmail(ctx context.Context, email string) (User, error) {
+	return m.findOne(ctx, bson.M{"email": email})
+}
+
+func (m mongoUserRepository) GetByContactNumber(ctx context.Context, contactNo string) (User, error) {
+	return m.findOne(ctx, bson.M{"contactNo": contactNo})
+}
+
+func (m mongoUserRepository) ListByIDs(ctx context.Context, ids []string) ([]User, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	var objectIDs []primitive.ObjectID
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, Invalid(FieldError{Field: "id", Message: "invalid ID format: " + id})
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	users := []User{}
+	err := findAll(ctx, m.collection, bson.M{"_id": bson.M{"$in": objectIDs}}, &users)
+	return users, err
+}
+
+func (m mongoUserRepository) ListByOrganisationName(ctx context.Context, orgName string) ([]User, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	users := []User{}
+	err := findAll(ctx, m.collection, bson.M{"orgName": orgName}, &users)
+	return users, err
+}
+
+func (m mongoUserRepository) ListByType(ctx context.Context, userType string) ([]User, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	users := []User{}
+	err := findAll(ctx, m.collection, bson.M{"userType": userType}, &users, options.Find().SetSort(bson.M{"_id": 1}))
+	return users, err
+}
+
+func (m mongoUserRepository) Insert(ctx context.Context, user User) (string, error) {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	res, err := m.collection.InsertOne(ctx, user)
+	// The unique indexes catch signups racing each other past the lookups in Signup
+	if mongo.IsDuplicateKeyError(err) {
+		if strings.Contains(err.Error(), "contactNo_unique") {
+			return "", Conflict("contact number already exists")
+		}
+		return "", Conflict("email already exists")
+	}
+	if err != nil {
+		return "", err
+	}
+	return insertedID(res), nil
+}
+
+// update applies update to the user id
+func (m mongoUserRepository) update(ctx context.Context, id string, update bson.M) error {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return NotFound("user")
+	}
+
+	res, err := m.collection.UpdateOne(ctx, bson.M{"_id": mongoID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return NotFound("user")
+	}
+	return nil
+}
+
+func (m mongoUserRepository) SetAvailability(ctx context.Context, id string, availability Availability) error {
+	return m.update(ctx, id, bson.M{"$set": bson.M{"availability": availability}})
+}
+
+func (m mongoUserRepository) SetRole(ctx context.Context, id, role string) error {
+	if role == "" {
+		return m.update(ctx, id, bson.M{"$unset": bson.M{"role": ""}})
+	}
+	return m.update(ctx, id, bson.M{"$set": bson.M{"role": role}})
+}
+
+func (m mongoUserRepository) SetPassword(ctx context.Context, id, hash string) error {
+	return m.update(ctx, id, bson.M{"$set": bson.M{"password": hash}})
+}
+
+func (m mongoUserRepository) SetDeactivatedAt(ctx context.Context, id string, at *time.Time) error {
+	if at == nil {
+		return m.update(ctx, id, bson.M{"$unset": bson.M{"deactivatedAt": ""}})
+	}
+	return m.update(ctx, id, bson.M{"$set": bson.M{"deactivatedAt": *at}})
+}
+
+type mongoHistoryRepository struct {
+	collection *mongo.Collection
+	deadlines
+}
+
+func (m mongoHistoryRepository) Append(ctx context.Context, entry HistoryEntry) error {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	_, err := m.collection.InsertOne(ctx, entry)
+	return err
+}
+
+func (m mongoHistoryRepository) List(ctx context.Context, todoID string, page, limit int) ([]HistoryEntry, int64, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	filter := bson.M{"todoId": todoID}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	entries := []HistoryEntry{}
+	if err := findAll(ctx, m.collection, filter, &entries, opts); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+type mongoTemplateRepository struct {
+	collection *mongo.Collection
+	deadlines
+}
+
+func (m mongoTemplateRepository) ListByOrganisation(ctx context.Context, orgIDs []string, orgName string) ([]Template, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	templates := []Template{}
+	err := findAll(ctx, m.collection, organisationMatch(orgIDs, orgName), &templates)
+	return templates, err
+}
+
+func (m mongoTemplateRepository) Get(ctx context.Context, id string) (Template, error) {
+	ctx, cancel := m.read(ctx)
+	defer cancel()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Template{}, NotFound("template")
+	}
+
+	var template Template
+	err = m.collection.FindOne(ctx, bson.M{"_id": mongoID}).Decode(&template)
+	if err != nil {
+		return Template{}, notFoundOr(err, "template")
+	}
+
+	return template, nil
+}
+
+func (m mongoTemplateRepository) Insert(ctx context.Context, template Template) (string, error) {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	res, err := m.collection.InsertOne(ctx, template)
+	if err != nil {
+		return "", err
+	}
+	return insertedID(res), nil
+}
+
+func (m mongoTemplateRepository) Update(ctx context.Context, id string, entry Template) error {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return NotFound("template")
+	}
+
+	// An empty $set is rejected, so with nothing to change only check the template exists
+	set := entry.changes()
+	if len(set) == 0 {
+		err := m.collection.FindOne(ctx, bson.M{"_id": mongoID}).Err()
+		return notFoundOr(err, "template")
+	}
+
+	res, err := m.collection.UpdateOne(ctx, bson.M{"_id": mongoID}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return NotFound("template")
+	}
+
+	return nil
+}
+
+func (m mongoTemplateRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := m.write(ctx)
+	defer cancel()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return NotFound("template")
+	}
+
+	res, err := m.collection.DeleteOne(ctx, bson.M{"_id": mongoID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return NotFound("template")
+	}
+
+	return nil
+}
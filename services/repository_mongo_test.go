@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTodoUpdate(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Todo
+		want  string
+	}{
+		{
+			"without volunteers",
+			Todo{Task: "Sort donations", Completed: true},
+			`{"$set": {"task": "Sort donations","completed": true}}`,
+		},
+		{
+			"with volunteers",
+			Todo{Task: "Sort donations", Volunteer: []Volunteer{{VolunteerID: "ada"}}},
+			`{"$set": {"task": "Sort donations","completed": false},"$push": {"volunteer": {"$each": [{"volunteerId": "ada"}]}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Marshalled as the driver sends it, so a null $each would show up here
+			raw, err := bson.Marshal(todoUpdate(tt.entry))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bson.Raw(raw).String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
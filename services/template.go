@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+
+	"github.com/volunteerService-backend/logging"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Template holds the default values an organisation reuses when creating todos
+type Template struct {
+	ID               string `json:"id,omitempty" bson:"_id,omitempty"`
+	Name             string `json:"name,omitempty" bson:"name,omitempty"`
+	OrganisationID   string `json:"orgId,omitempty" bson:"orgId,omitempty"`
+	OrganisationName string `json:"orgName,omitempty" bson:"orgName,omitempty"`
+	Task             string `json:"task,omitempty" bson:"task,omitempty"`
+	Description      string `json:"description,omitempty" bson:"description,omitempty"`
+	VolunteerType    string `json:"volType,omitempty" bson:"volType,omitempty"`
+	OrganisationType string `json:"orgType,omitempty" bson:"orgType,omitempty"`
+	VolunteersNeeded int    `json:"volNeeded,omitempty" bson:"volNeeded,omitempty"`
+}
+
+// changes returns the default values set in t by their bson names, the fields an update
+// of a template writes. Empty values are left out, as they mean the field isn't changed.
+func (t Template) changes() bson.M {
+	set := bson.M{}
+	for name, value := range map[string]string{
+		"name":        t.Name,
+		"task":        t.Task,
+		"description": t.Description,
+		"volType":     t.VolunteerType,
+		"orgType":     t.OrganisationType,
+	} {
+		if value != "" {
+			set[name] = value
+		}
+	}
+	if t.VolunteersNeeded != 0 {
+		set["volNeeded"] = t.VolunteersNeeded
+	}
+	return set
+}
+
+// merge returns t with the default values set in changes
+func (t Template) merge(changes Template) Template {
+	for _, field := range []struct{ to, from *string }{
+		{&t.Name, &changes.Name},
+		{&t.Task, &changes.Task},
+		{&t.Description, &changes.Description},
+		{&t.VolunteerType, &changes.VolunteerType},
+		{&t.OrganisationType, &changes.OrganisationType},
+	} {
+		if *field.from != "" {
+			*field.to = *field.from
+		}
+	}
+	if changes.VolunteersNeeded != 0 {
+		t.VolunteersNeeded = changes.VolunteersNeeded
+	}
+	return t
+}
+
+// GetTemplatesByOrganisationID returns the templates of the organisation with the given ID,
+// along with older templates that only carry its current name, orgName
+func (m *Models) GetTemplatesByOrganisationID(ctx context.Context, id, orgName string) ([]Template, error) {
+	templates, err := m.templates.ListByOrganisation(ctx, []string{id}, orgName)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding templates by organisation", "error", err)
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetTemplateById returns a single template based on its ID
+func (m *Models) GetTemplateById(ctx context.Context, id string) (Template, error) {
+	template, err := m.templates.Get(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding template", "error", err)
+		return Template{}, err
+	}
+
+	return template, nil
+}
+
+// InsertTemplate creates a new template for actor's organisation and returns its ID
+func (m *Models) InsertTemplate(ctx context.Context, entry Template, actor string) (string, error) {
+	entry.ID = ""
+	entry.OrganisationID = m.organisationIDFor(ctx, entry.OrganisationName, actor)
+
+	id, err := m.templates.Insert(ctx, entry)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error inserting template", "error", err)
+		return "", err
+	}
+
+	return id, nil
+}
+
+// UpdateTemplate changes the default values set in entry, keeping the rest of the template
+// and its organisation
+func (m *Models) UpdateTemplate(ctx context.Context, id string, entry Template) error {
+	err := m.templates.Update(ctx, id, entry)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error updating template", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTemplate deletes a template by its ID
+func (m *Models) DeleteTemplate(ctx context.Context, id string) error {
+	err := m.templates.Delete(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error deleting template", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// AuthorizeTemplate checks that the user with email, the authenticated caller, belongs to the
+// organisation owning template
+func (m *Models) AuthorizeTemplate(ctx context.Context, email string, template Template) error {
+	if email == "" {
+		return Unauthorized("Authentication required")
+	}
+	user, err := m.users.GetByEmail(ctx, email)
+	if err != nil {
+		return Unauthorized("Authentication required")
+	}
+
+	if !template.IsOwnedBy(user) {
+		return Forbidden("Only members of the organisation can see this")
+	}
+	return nil
+}
+
+// CreateTodoFromTemplate creates a todo from the template's defaults.
+// Any field set in overrides takes precedence over the template value.
+func (m *Models) CreateTodoFromTemplate(ctx context.Context, id string, overrides Todo, actor string) (string, error) {
+	template, err := m.GetTemplateById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	entry := overrides
+	entry.ID = ""
+	entry.OrganisationName = template.OrganisationName
+	entry.OrganisationID = template.OrganisationID
+	if entry.OrganisationID == "" {
+		entry.OrganisationID = m.organisationIDFor(ctx, entry.OrganisationName, actor)
+	}
+	if entry.Task == "" {
+		entry.Task = template.Task
+	}
+	if entry.Description == "" {
+		entry.Description = template.Description
+	}
+	if entry.VolunteerType == "" {
+		entry.VolunteerType = template.VolunteerType
+	}
+	if entry.OrganisationType == "" {
+		entry.OrganisationType = template.OrganisationType
+	}
+	if entry.VolunteersNeeded == 0 {
+		entry.VolunteersNeeded = template.VolunteersNeeded
+	}
+
+	return m.insertTodo(ctx, entry, actor)
+}
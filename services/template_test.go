@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/volunteerService-backend/config"
+	"github.com/volunteerService-backend/db"
+)
+
+// testBackends returns the repositories of every storage backend available to the tests.
+// MongoDB is only used when MONGO_TEST_URI points at a server; each run gets its own
+// database, dropped once the test is done.
+func testBackends(t *testing.T) map[string]Repositories {
+	t.Helper()
+
+	backends := map[string]Repositories{"memory": NewMemoryRepositories()}
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		return backends
+	}
+	cfg := config.Default().Mongo
+	cfg.URI = uri
+	client, err := db.ConnectToMongo(cfg)
+	if err != nil {
+		t.Fatalf("connecting to %s: %v", uri, err)
+	}
+	database := fmt.Sprintf("volunteer_test_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		ctx := context.Background()
+		client.Database(database).Drop(ctx)
+		client.Disconnect(ctx)
+	})
+
+	backends["mongo"] = NewMongoRepositories(client, database, cfg.Timeouts)
+	return backends
+}
+
+func TestTemplateUpdate(t *testing.T) {
+	for name, repos := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			original := Template{
+				Name:             "Weekly sort",
+				OrganisationID:   "org-1",
+				OrganisationName: "Helping Hands",
+				Task:             "Sort donations",
+				Description:      "Sort the week's donations",
+				VolunteerType:    "community",
+				VolunteersNeeded: 3,
+			}
+			id, err := repos.Templates.Insert(ctx, original)
+			if err != nil {
+				t.Fatal(err)
+			}
+			original.ID = id
+
+			// Only the values set are changed, and never the owner
+			err = repos.Templates.Update(ctx, id, Template{Task: "Sort clothes", OrganisationName: "Food Bank"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := original
+			want.Task = "Sort clothes"
+			if got, _ := repos.Templates.Get(ctx, id); got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+
+			if err := repos.Templates.Update(ctx, id, Template{}); err != nil {
+				t.Errorf("got %v for an update changing nothing", err)
+			}
+			if got, _ := repos.Templates.Get(ctx, id); got != want {
+				t.Errorf("got %+v after an empty update, want %+v", got, want)
+			}
+
+			for _, missing := range []string{"65f000000000000000000000", "not-an-id"} {
+				if err := repos.Templates.Update(ctx, missing, Template{Task: "x"}); !errors.Is(err, ErrNotFound) {
+					t.Errorf("got %v updating template %s, want not found", err, missing)
+				}
+				if err := repos.Templates.Update(ctx, missing, Template{}); !errors.Is(err, ErrNotFound) {
+					t.Errorf("got %v for an empty update of template %s, want not found", err, missing)
+				}
+			}
+		})
+	}
+}
@@ -2,226 +2,287 @@ package services
 
 import (
 	"context"
-	"log"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/volunteerService-backend/logging"
+	"github.com/volunteerService-backend/metrics"
 )
 
 type Volunteer struct {
-	VolunteerID   string `json:"volunteerId,omitempty" bson:"volunteerId,omitempty"`
-	VolunteerName string `json:"volunteerName,omitempty" bson:"volunteerName,omitempty"`
+	VolunteerID   string    `json:"volunteerId,omitempty" bson:"volunteerId,omitempty"`
+	VolunteerName string    `json:"volunteerName,omitempty" bson:"volunteerName,omitempty" validate:"max=200"`
+	JoinedAt      time.Time `json:"joinedAt,omitempty" bson:"joinedAt,omitempty"`
 }
 
 type Todo struct {
 	ID               string      `json:"id,omitempty" bson:"_id,omitempty"`
-	Task             string      `json:"task,omitempty" bson:"task,omitempty"`
-	Description      string      `json:"description,omitempty" bson:"description,omitempty"`
-	OrganisationName string      `json:"orgName,omitempty" bson:"orgName,omitempty"`
-	VolunteerType    string      `json:"volType,omitempty" bson:"volType,omitempty"`
-	OrganisationType string      `json:"orgType,omitempty" bson:"orgType,omitempty"`
+	Task             string      `json:"task,omitempty" bson:"task,omitempty" validate:"required,max=200"`
+	Description      string      `json:"description,omitempty" bson:"description,omitempty" validate:"max=2000"`
+	OrganisationID   string      `json:"orgId,omitempty" bson:"orgId,omitempty"`
+	OrganisationName string      `json:"orgName,omitempty" bson:"orgName,omitempty" validate:"max=200"`
+	VolunteerType    string      `json:"volType,omitempty" bson:"volType,omitempty" validate:"omitempty,voltype"`
+	OrganisationType string      `json:"orgType,omitempty" bson:"orgType,omitempty" validate:"omitempty,orgtype"`
 	Completed        bool        `json:"completed" bson:"completed"`
 	Time             time.Time   `json:"time,omitempty" bson:"time,omitempty"`
-	Volunteer        []Volunteer `json:"volunteer,omitempty" bson:"volunteer,omitempty"` // Nested Volunteer struct
-}
-
-var client *mongo.Client
-
-// New is used to initialize the mongo client for the Todo struct
-func New(mongo *mongo.Client) Todo {
-	client = mongo
-	return Todo{}
-}
-
-// returnCollectionPointer returns a pointer to the 'todos' collection
-func returnCollectionPointer(collection string) *mongo.Collection {
-	return client.Database("volunteerService-backend-db").Collection(collection)
+	EndTime          time.Time   `json:"endTime,omitempty" bson:"endTime,omitempty"`
+	CreatedAt        time.Time   `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	VolunteersNeeded int         `json:"volNeeded,omitempty" bson:"volNeeded,omitempty" validate:"min=0,max=10000"`
+	Volunteer        []Volunteer `json:"volunteer,omitempty" bson:"volunteer,omitempty" validate:"max=1000,dive"` // Nested Volunteer struct
 }
 
 // GetAllTodos returns all the todos from the db
-func (t *Todo) GetAllTodos() ([]Todo, error) {
-	collection := returnCollectionPointer("todos")
-	var todos []Todo
-
-	cursor, err := collection.Find(context.TODO(), bson.D{})
+func (m *Models) GetAllTodos(ctx context.Context) ([]Todo, error) {
+	todos, err := m.todos.List(ctx)
 	if err != nil {
-		log.Fatal(err)
+		logging.FromContext(ctx).Error("Error finding todos", "error", err)
 		return nil, err
 	}
 
-	defer cursor.Close(context.Background())
-
-	for cursor.Next(context.Background()) {
-		var todo Todo
-		cursor.Decode(&todo)
-		todos = append(todos, todo)
+	if err := m.resolveNames(ctx, todos); err != nil {
+		logging.FromContext(ctx).Error("Error resolving names", "error", err)
 	}
 
 	return todos, nil
 }
 
 // GetTodoById returns a single todo based on its ID
-func (t *Todo) GetTodoById(id string) (Todo, error) {
-	collection := returnCollectionPointer("todos")
-	var todo Todo
-
-	mongoID, err := primitive.ObjectIDFromHex(id)
+func (m *Models) GetTodoById(ctx context.Context, id string) (Todo, error) {
+	todo, err := m.todos.Get(ctx, id)
 	if err != nil {
+		logging.FromContext(ctx).Error("Error finding todo", "error", err)
 		return Todo{}, err
 	}
 
-	err = collection.FindOne(context.Background(), bson.M{"_id": mongoID}).Decode(&todo)
+	todos := []Todo{todo}
+	if err := m.resolveNames(ctx, todos); err != nil {
+		logging.FromContext(ctx).Error("Error resolving names", "error", err)
+	}
+
+	return todos[0], nil
+}
+
+// findTodo returns the todo as stored, without resolving names, or nil if it doesn't exist
+func (m *Models) findTodo(ctx context.Context, id string) *Todo {
+	todo, err := m.todos.Get(ctx, id)
 	if err != nil {
-		log.Println(err)
-		return Todo{}, err
+		return nil
 	}
 
-	return todo, nil
+	return &todo
 }
 
-// InsertTodo creates a new todo in the collection
-func (t *Todo) InsertTodo(entry Todo) error {
-	collection := returnCollectionPointer("todos")
+// InsertTodo creates a new todo in the collection and returns its ID.
+// The actor is recorded in the todo's history.
+func (m *Models) InsertTodo(ctx context.Context, entry Todo, actor string) (string, error) {
+	// The organisation reference is never taken from the client
+	entry.OrganisationID = m.organisationIDFor(ctx, entry.OrganisationName, actor)
+
+	return m.insertTodo(ctx, entry, actor)
+}
+
+// insertTodo stores entry, whose OrganisationID has already been worked out on the server
+func (m *Models) insertTodo(ctx context.Context, entry Todo, actor string) (string, error) {
+	if err := entry.Validate(); err != nil {
+		return "", err
+	}
 
 	// If the Time is not set in the request, set it to the current time
 	if entry.Time.IsZero() {
 		entry.Time = time.Now()
 	}
+	entry.CreatedAt = time.Now()
 
 	// Ensure the Volunteer field is not carrying over from previous operations
 	entry.Volunteer = nil
 
 	// Insert the entire 'entry' object as it contains all fields
-	_, err := collection.InsertOne(context.TODO(), entry)
+	id, err := m.todos.Insert(ctx, entry)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error inserting todo", "error", err)
+		return "", err
+	}
+
+	metrics.TodosCreated.Inc()
+
+	entry.ID = id
+	m.recordHistory(ctx, entry, HistoryCreate, actor, diffTodos(nil, &entry))
+
+	return id, nil
+}
+
+// UpdateTodo sets the task and completion of a todo and appends any new volunteers.
+// The actor is recorded in the todo's history.
+func (m *Models) UpdateTodo(ctx context.Context, id string, entry Todo, actor string) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Debug("Updating todo", "id", id, "todo", entry)
+
+	// Record when each new volunteer joined so time-to-fill can be reported
+	for i := range entry.Volunteer {
+		if entry.Volunteer[i].JoinedAt.IsZero() {
+			entry.Volunteer[i].JoinedAt = time.Now()
+		}
+	}
+
+	before := m.findTodo(ctx, id)
+
+	err := m.todos.Update(ctx, id, entry)
 	if err != nil {
-		log.Println("Error inserting todo:", err)
+		logging.FromContext(ctx).Error("Error updating todo", "error", err)
 		return err
 	}
 
+	metrics.VolunteerJoins.Add(float64(len(entry.Volunteer)))
+
+	if before != nil {
+		m.recordTodoChange(ctx, actor, before, m.findTodo(ctx, id))
+	}
+
 	return nil
 }
 
-func (t *Todo) UpdateTodo(id string, entry Todo) (*mongo.UpdateResult, error) {
-	collection := returnCollectionPointer("todos")
-	mongoID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return nil, err
+// JoinTodo signs volunteer up for the todo, leaving its task and completion as they are.
+// Schedule conflicts are not checked here; use CheckSchedule first. A volunteer who has
+// already joined is a conflict.
+func (m *Models) JoinTodo(ctx context.Context, id string, volunteer Volunteer, actor string) error {
+	if volunteer.VolunteerID == "" {
+		return Invalid(FieldError{Field: "volunteerId", Message: "is required"})
+	}
+	existing := m.findTodo(ctx, id)
+	if existing == nil {
+		return NotFound("todo")
+	}
+	for _, joined := range existing.Volunteer {
+		if joined.VolunteerID == volunteer.VolunteerID {
+			return Conflict("volunteer has already joined the todo")
+		}
 	}
-	log.Println(entry)
 
-	update := bson.D{
-		{"$set", bson.D{
-			{"task", entry.Task},
-			{"completed", entry.Completed},
-		}},
-		{"$push", bson.D{
-			{"volunteer", bson.M{"$each": entry.Volunteer}}, // Append the new volunteers to the array
-		}},
+	entry := Todo{
+		Task:      existing.Task,
+		Completed: existing.Completed,
+		Volunteer: []Volunteer{volunteer},
 	}
+	return m.UpdateTodo(ctx, id, entry, actor)
+}
 
-	res, err := collection.UpdateOne(
-		context.Background(),
-		bson.M{"_id": mongoID},
-		update,
-	)
+// AuthorizeVolunteer checks the user with email may sign volunteerID up for the todo or take
+// them off it: only the volunteer themselves or the organisation owning the todo can
+func (m *Models) AuthorizeVolunteer(ctx context.Context, email, todoID, volunteerID string) error {
+	if email == "" {
+		return Unauthorized("Authentication required")
+	}
+	user, err := m.users.GetByEmail(ctx, email)
 	if err != nil {
-		log.Println(err)
-		return nil, err
+		return Unauthorized("Authentication required")
+	}
+
+	todo := m.findTodo(ctx, todoID)
+	if todo == nil {
+		return NotFound("todo")
+	}
+
+	if user.ID != volunteerID && !todo.IsOwnedBy(user) {
+		return Forbidden("Only the volunteer or the owning organisation can change this")
 	}
 
-	return res, nil
+	return nil
 }
 
-// DeleteTodo deletes a todo by its ID
-func (t *Todo) DeleteTodo(id string) error {
-	collection := returnCollectionPointer("todos")
-	mongoID, err := primitive.ObjectIDFromHex(id)
+// LeaveTodo takes the volunteer off the todo.
+// The actor is recorded in the todo's history.
+func (m *Models) LeaveTodo(ctx context.Context, id, volunteerID, actor string) error {
+	before := m.findTodo(ctx, id)
+
+	err := m.todos.RemoveVolunteer(ctx, id, volunteerID)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx).Error("Error removing volunteer", "error", err)
 		return err
 	}
 
-	_, err = collection.DeleteOne(
-		context.Background(),
-		bson.M{"_id": mongoID},
-	)
+	if before != nil {
+		m.recordTodoChange(ctx, actor, before, m.findTodo(ctx, id))
+	}
+
+	return nil
+}
+
+// DeleteTodo deletes a todo by its ID.
+// The actor is recorded in the todo's history.
+func (m *Models) DeleteTodo(ctx context.Context, id string, actor string) error {
+	before := m.findTodo(ctx, id)
+
+	err := m.todos.Delete(ctx, id)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx).Error("Error deleting todo", "error", err)
 		return err
 	}
 
+	if before != nil {
+		m.recordHistory(ctx, *before, HistoryDelete, actor, diffTodos(before, nil))
+	}
+
 	return nil
 }
 
-// GetTodosByOrg retrieves todos filtered by OrganisationName
-func (t *Todo) GetTodosByOrg(orgName string) ([]Todo, error) {
-	collection := returnCollectionPointer("todos")
+// CloneTodo copies an existing todo into a new, open todo without its volunteers
+func (m *Models) CloneTodo(ctx context.Context, id string, actor string) (string, error) {
+	original, err := m.GetTodoById(ctx, id)
+	if err != nil {
+		return "", err
+	}
 
-	// Build filter to search by OrganisationName
-	filter := bson.M{"orgName": orgName}
+	original.ID = ""
+	original.Completed = false
+	original.Volunteer = nil
 
-	var todos []Todo
+	// The copy belongs to the same organisation as the stored original
+	return m.insertTodo(ctx, original, actor)
+}
 
-	// Query the database
-	cursor, err := collection.Find(context.TODO(), filter)
+// GetTodosByOrg retrieves todos filtered by OrganisationName
+func (m *Models) GetTodosByOrg(ctx context.Context, orgName string) ([]Todo, error) {
+	// Search by the organisations currently called orgName
+	orgIDs, err := m.findOrganisationIDs(ctx, orgName)
 	if err != nil {
-		log.Println("Error finding todos by orgName:", err)
 		return nil, err
 	}
-	defer cursor.Close(context.TODO())
 
-	// Iterate through the results and append to todos slice
-	for cursor.Next(context.TODO()) {
-		var todo Todo
-		if err := cursor.Decode(&todo); err != nil {
-			log.Println("Error decoding todo:", err)
-			continue
-		}
-		todos = append(todos, todo)
-	}
+	return m.listOrganisationTodos(ctx, orgIDs, orgName)
+}
 
-	// Check if there was any error while iterating the cursor
-	if err := cursor.Err(); err != nil {
-		log.Println("Error with cursor:", err)
+// GetTodosByOrganisationID retrieves the todos of the organisation with the given ID, along
+// with older todos that only carry its current name, orgName
+func (m *Models) GetTodosByOrganisationID(ctx context.Context, id, orgName string) ([]Todo, error) {
+	return m.listOrganisationTodos(ctx, []string{id}, orgName)
+}
+
+// listOrganisationTodos retrieves the todos referencing any of orgIDs, or carrying only orgName
+func (m *Models) listOrganisationTodos(ctx context.Context, orgIDs []string, orgName string) ([]Todo, error) {
+	todos, err := m.todos.ListByOrganisation(ctx, orgIDs, orgName)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error finding todos by organisation", "error", err)
 		return nil, err
 	}
 
+	if err := m.resolveNames(ctx, todos); err != nil {
+		logging.FromContext(ctx).Error("Error resolving names", "error", err)
+	}
+
 	return todos, nil
 }
 
 // GetTodosByVolType retrieves todos filtered by VolunteerType
-func (t *Todo) GetTodosByVolType(volType string) ([]Todo, error) {
-	collection := returnCollectionPointer("todos")
-
-	// Build filter to search by VolunteerType
-	filter := bson.M{"volType": volType}
-
-	var todos []Todo
-
-	// Query the database
-	cursor, err := collection.Find(context.TODO(), filter)
+func (m *Models) GetTodosByVolType(ctx context.Context, volType string) ([]Todo, error) {
+	todos, err := m.todos.ListByVolunteerType(ctx, volType)
 	if err != nil {
-		log.Println("Error finding todos by volType:", err)
+		logging.FromContext(ctx).Error("Error finding todos by volType", "error", err)
 		return nil, err
 	}
-	defer cursor.Close(context.TODO())
 
-	// Iterate through the results and append to todos slice
-	for cursor.Next(context.TODO()) {
-		var todo Todo
-		if err := cursor.Decode(&todo); err != nil {
-			log.Println("Error decoding todo:", err)
-			continue
-		}
-		todos = append(todos, todo)
-	}
-
-	// Check if there was any error while iterating the cursor
-	if err := cursor.Err(); err != nil {
-		log.Println("Error with cursor:", err)
-		return nil, err
+	if err := m.resolveNames(ctx, todos); err != nil {
+		logging.FromContext(ctx).Error("Error resolving names", "error", err)
 	}
 
 	return todos, nil
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Accepted values of the enumerated user and todo fields
+var (
+	UserTypes         = []string{"volunteer", "organisation"}
+	VolunteerTypes    = []string{"general", "community", "education", "environment", "healthcare", "disaster-relief", "animal-welfare", "technology"}
+	OrganisationTypes = []string{"charity", "ngo", "community", "educational", "healthcare", "religious", "government", "corporate"}
+)
+
+// minPasswordLength is the shortest password accepted at signup
+const minPasswordLength = 8
+
+// maxPasswordBytes is the longest password accepted, in bytes rather than characters,
+// as bcrypt ignores everything past it
+const maxPasswordBytes = 72
+
+// validate checks the `validate` struct tags of User and Todo. Fields are reported by their JSON names.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return isStrongPassword(fl.Field().String())
+	})
+	v.RegisterValidation("usertype", oneOf(UserTypes))
+	v.RegisterValidation("voltype", oneOf(VolunteerTypes))
+	v.RegisterValidation("orgtype", oneOf(OrganisationTypes))
+
+	v.RegisterStructValidation(validateTodoTimes, Todo{})
+
+	return v
+}
+
+// oneOf returns a validation func accepting only the given values
+func oneOf(values []string) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		for _, value := range values {
+			if fl.Field().String() == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isStrongPassword requires a length bcrypt can hash in full and a mix of upper case,
+// lower case and digits
+func isStrongPassword(password string) bool {
+	if len(password) < minPasswordLength || len([]byte(password)) > maxPasswordBytes {
+		return false
+	}
+
+	var upper, lower, digit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// validateTodoTimes makes sure a todo doesn't end before it starts
+func validateTodoTimes(sl validator.StructLevel) {
+	todo := sl.Current().Interface().(Todo)
+	if !todo.EndTime.IsZero() && !todo.Time.IsZero() && !todo.EndTime.After(todo.Time) {
+		sl.ReportError(todo.EndTime, "endTime", "EndTime", "aftertime", "")
+	}
+}
+
+// Validate checks a signup request, reporting every invalid field at once
+func (u User) Validate() error {
+	return validationError(validate.Struct(u))
+}
+
+// validatePassword applies the signup rules for passwords to a new password on its own
+func validatePassword(password string) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(validate.Var(password, "required,password"), &validationErrs) {
+		return nil
+	}
+
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, FieldError{Field: "password", Message: fieldMessage(fe)})
+	}
+	return Invalid(fields...)
+}
+
+// Validate checks a todo before it is stored, reporting every invalid field at once
+func (t Todo) Validate() error {
+	return validationError(validate.Struct(t))
+}
+
+// validationError converts the errors of the validator to an Invalid error with a message per field
+func validationError(err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		// Drop the struct name, e.g. "Todo.volunteer[0].volunteerId" becomes "volunteer[0].volunteerId"
+		field := fe.Namespace()
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		fields = append(fields, FieldError{Field: field, Message: fieldMessage(fe)})
+	}
+	return Invalid(fields...)
+}
+
+// fieldMessage describes a failed validation rule in words
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "e164":
+		return "must be an E.164 phone number, e.g. +447911123456"
+	case "password":
+		return fmt.Sprintf("must be %d characters to %d bytes long and contain upper case, lower case and a digit", minPasswordLength, maxPasswordBytes)
+	case "usertype":
+		return "must be one of " + strings.Join(UserTypes, ", ")
+	case "voltype":
+		return "must be one of " + strings.Join(VolunteerTypes, ", ")
+	case "orgtype":
+		return "must be one of " + strings.Join(OrganisationTypes, ", ")
+	case "max":
+		switch fe.Kind() {
+		case reflect.String:
+			return "must be at most " + fe.Param() + " characters"
+		case reflect.Slice:
+			return "must have at most " + fe.Param() + " entries"
+		}
+		return "must be at most " + fe.Param()
+	case "min":
+		return "must be at least " + fe.Param()
+	case "aftertime":
+		return "must be after time"
+	}
+	return "is invalid"
+}
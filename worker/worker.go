@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Worker statuses reported by Group.Status
+const (
+	StatusRunning = "running"
+	StatusStopped = "stopped"
+	StatusFailed  = "failed"
+)
+
+// Group runs named background workers and stops them together on shutdown
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	statuses map[string]string
+}
+
+// NewGroup returns an empty group ready to run workers
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{
+		ctx:      ctx,
+		cancel:   cancel,
+		statuses: map[string]string{},
+	}
+}
+
+// Go starts fn in the background. fn should return once its context is cancelled.
+func (g *Group) Go(name string, fn func(ctx context.Context) error) {
+	g.setStatus(name, StatusRunning)
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		if err := fn(g.ctx); err != nil && g.ctx.Err() == nil {
+			slog.Error("Worker failed", "worker", name, "error", err)
+			g.setStatus(name, StatusFailed)
+			return
+		}
+		g.setStatus(name, StatusStopped)
+	}()
+}
+
+// Stop cancels every worker and waits for them to return, or for ctx to expire
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Status returns the current status of every worker by name
+func (g *Group) Status() map[string]string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	statuses := make(map[string]string, len(g.statuses))
+	for name, status := range g.statuses {
+		statuses[name] = status
+	}
+	return statuses
+}
+
+func (g *Group) setStatus(name, status string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.statuses[name] = status
+}